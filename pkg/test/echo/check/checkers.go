@@ -17,9 +17,11 @@ package check
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 
@@ -121,6 +123,67 @@ func ErrorContains(expected string) Checker {
 	}
 }
 
+// ConnectionReset checks that the connection was reset by the server. The reset is either observed directly
+// by the client as an error, or reported by Envoy as a 503 with an upstream reset reason.
+func ConnectionReset() Checker {
+	return func(rs echo.Responses, err error) error {
+		if err != nil {
+			if isResetError(err) {
+				return nil
+			}
+			return fmt.Errorf("expected connection reset, but encountered %v", err)
+		}
+		return Each(func(r echo.Response) error {
+			if r.Code == strconv.Itoa(http.StatusServiceUnavailable) && strings.Contains(r.RawContent, "reset") {
+				return nil
+			}
+			return fmt.Errorf("expected connection reset, received response code `%s`", r.Code)
+		})(rs, err)
+	}
+}
+
+// Rejected checks that the server did not successfully serve the request, such as a plaintext request to an
+// endpoint that requires mTLS. The call must either fail because the connection was rejected, with a TLS
+// error, a connection reset or a connection closed before any response, or every response must have a
+// non-200 status code. Other errors, such as DNS failures or timeouts, do not show that the server rejected
+// the request and fail the check.
+func Rejected() Checker {
+	return func(rs echo.Responses, err error) error {
+		if err != nil {
+			if isRejectedError(err) {
+				return nil
+			}
+			return fmt.Errorf("expected request to be rejected, but encountered %v", err)
+		}
+		return Each(func(r echo.Response) error {
+			if r.Code == strconv.Itoa(http.StatusOK) {
+				return errors.New("expected request to be rejected, but it succeeded")
+			}
+			return nil
+		})(rs, err)
+	}
+}
+
+// isRejectedError returns true if the error shows that the server refused the connection: the TLS handshake
+// failed, the connection was reset, or it was closed before any response was received.
+func isRejectedError(err error) bool {
+	if isResetError(err) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, ": EOF") || strings.Contains(msg, "tls:") ||
+		strings.Contains(msg, "handshake failure")
+}
+
+// isResetError returns true if the error was caused by the peer resetting the connection with a TCP RST, or
+// the HTTP/2 or gRPC stream with a RST_STREAM.
+func isResetError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset by peer") || strings.Contains(msg, "ECONNRESET") ||
+		strings.Contains(msg, "RST_STREAM") ||
+		(strings.Contains(msg, "stream error") && strings.Contains(msg, "received from peer"))
+}
+
 // OK is a shorthand for NoErrorAndStatus(200).
 func OK() Checker {
 	return NoErrorAndStatus(http.StatusOK)
@@ -161,6 +224,70 @@ func TooManyRequests() Checker {
 	}
 }
 
+// ResponseCount checks that exactly n responses were received.
+func ResponseCount(n int) Checker {
+	return func(rs echo.Responses, _ error) error {
+		if rs.Len() != n {
+			return fmt.Errorf("expected %d responses, received %d", n, rs.Len())
+		}
+		return nil
+	}
+}
+
+// ResponseCountAtLeast checks that at least n responses were received.
+func ResponseCountAtLeast(n int) Checker {
+	return func(rs echo.Responses, _ error) error {
+		if rs.Len() < n {
+			return fmt.Errorf("expected at least %d responses, received %d", n, rs.Len())
+		}
+		return nil
+	}
+}
+
+// LatencyBelow checks that no response took longer than d to complete, as measured by the forwarding echo.
+func LatencyBelow(d time.Duration) Checker {
+	return Each(func(r echo.Response) error {
+		if r.Latency > d {
+			return fmt.Errorf("expected latency below %v, got %v", d, r.Latency)
+		}
+		return nil
+	})
+}
+
+// StreamMessages checks that each gRPC stream echoed exactly n messages before it was closed.
+func StreamMessages(n int) Checker {
+	return Each(func(r echo.Response) error {
+		if r.StreamMessages != n {
+			return fmt.Errorf("expected %d messages to be echoed on the stream, got %d", n, r.StreamMessages)
+		}
+		return nil
+	})
+}
+
+// Retried checks that Envoy retried the requests at least min times in total, based on the
+// X-Envoy-Attempt-Count header received by the server. Since only some requests may fail, the retries
+// are summed across all responses rather than required of each one.
+func Retried(min int) Checker {
+	return func(rs echo.Responses, _ error) error {
+		retries := 0
+		for _, r := range rs {
+			v := r.RequestHeaders.Get("X-Envoy-Attempt-Count")
+			if v == "" {
+				return errors.New("expected X-Envoy-Attempt-Count header but not found")
+			}
+			attempts, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid X-Envoy-Attempt-Count %q: %v", v, err)
+			}
+			retries += attempts - 1
+		}
+		if retries < min {
+			return fmt.Errorf("expected at least %d retries, received %d", min, retries)
+		}
+		return nil
+	}
+}
+
 func Host(expected string) Checker {
 	return Each(func(r echo.Response) error {
 		if r.Host != expected {
@@ -188,6 +315,17 @@ func Alpn(expected string) Checker {
 	})
 }
 
+// UsedXDSResolver checks that each request was made using the gRPC xds:/// resolver, as is expected for
+// calls from proxyless gRPC workloads.
+func UsedXDSResolver() Checker {
+	return Each(func(r echo.Response) error {
+		if !r.UsedXDSResolver() {
+			return fmt.Errorf("expected request to use the xds resolver, requested %s", r.RequestURL)
+		}
+		return nil
+	})
+}
+
 func MTLSForHTTP() Checker {
 	return Each(func(r echo.Response) error {
 		if !strings.HasPrefix(r.RequestURL, "http://") &&
@@ -264,6 +402,17 @@ func ResponseHeaders(expected map[string]string) Checker {
 	})
 }
 
+// RoutedToSubset checks that each response came from the given subset. Subsets are assumed to be named
+// after the version of the echo workloads they select.
+func RoutedToSubset(subset string) Checker {
+	return Each(func(r echo.Response) error {
+		if r.Version != subset {
+			return fmt.Errorf("expected response from subset %s, received version %s", subset, r.Version)
+		}
+		return nil
+	})
+}
+
 func Cluster(expected string) Checker {
 	return Each(func(r echo.Response) error {
 		if r.Cluster != expected {
@@ -273,6 +422,19 @@ func Cluster(expected string) Checker {
 	})
 }
 
+// ClusterIn checks that each response came from one of the given clusters. Unlike ReachedClusters, it does
+// not require every cluster to be reached, and it reports the index of each offending response.
+func ClusterIn(clusters cluster.Clusters) Checker {
+	return Each(func(r echo.Response) error {
+		for _, c := range clusters {
+			if r.Cluster == c.Name() {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected cluster in %v, received %s", clusters, r.Cluster)
+	})
+}
+
 func URL(expected string) Checker {
 	return Each(func(r echo.Response) error {
 		if r.URL != expected {
@@ -282,6 +444,47 @@ func URL(expected string) Checker {
 	})
 }
 
+// ReceivedPath checks that the server received the request with the given path, ignoring any query.
+func ReceivedPath(expected string) Checker {
+	return Each(func(r echo.Response) error {
+		path := strings.SplitN(r.URL, "?", 2)[0]
+		if path != expected {
+			return fmt.Errorf("expected path %s, received %s", expected, path)
+		}
+		return nil
+	})
+}
+
+// autoAllocatedVIPRange is the range from which Istio auto-allocates VIPs for ServiceEntry hosts.
+const autoAllocatedVIPRange = "240.240.0.0/16"
+
+// ResolvedToAutoAllocatedVIP checks that each DNS response resolved the host only to VIPs auto-allocated
+// by Istio. This must be used with calls using the DNS scheme.
+func ResolvedToAutoAllocatedVIP() Checker {
+	return ResolvedInRange(autoAllocatedVIPRange)
+}
+
+// ResolvedInRange checks that each DNS response resolved the host only to addresses within the given CIDR.
+// This must be used with calls using the DNS scheme.
+func ResolvedInRange(cidr string) Checker {
+	_, ipNet, cidrErr := net.ParseCIDR(cidr)
+	return Each(func(r echo.Response) error {
+		if cidrErr != nil {
+			return cidrErr
+		}
+		ips := r.Body()
+		if len(ips) == 0 {
+			return errors.New("expected resolved addresses, but none found")
+		}
+		for _, ip := range ips {
+			if parsed := net.ParseIP(ip); parsed == nil || !ipNet.Contains(parsed) {
+				return fmt.Errorf("expected addresses in %s, resolved %v", cidr, ips)
+			}
+		}
+		return nil
+	})
+}
+
 // ReachedClusters returns an error if there wasn't at least one response from each of the given clusters.
 // This can be used in combination with echo.Responses.Clusters(), for example:
 //     echoA[0].CallOrFail(t, ...).CheckReachedClusters(echoB.Clusters())
@@ -304,6 +507,20 @@ func ReachedClusters(clusters cluster.Clusters) Checker {
 	}
 }
 
+// StayedInCluster checks that all responses came from the given cluster, i.e. that no traffic spilled over
+// to other clusters.
+func StayedInCluster(c cluster.Cluster) Checker {
+	return func(r echo.Responses, err error) error {
+		hits := clusterDistribution(r)
+		for hitCluster := range hits {
+			if hitCluster != c.Name() {
+				return fmt.Errorf("expected all responses from %s, got %v", c.Name(), hits)
+			}
+		}
+		return nil
+	}
+}
+
 func clusterDistribution(r echo.Responses) map[string]int {
 	hits := map[string]int{}
 	for _, rr := range r {
@@ -0,0 +1,167 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"istio.io/istio/pkg/test/echo"
+)
+
+func TestRejected(t *testing.T) {
+	cases := []struct {
+		name    string
+		rs      echo.Responses
+		err     error
+		wantErr bool
+	}{
+		{
+			name: "connection closed",
+			err:  errors.New(`Get "http://10.0.0.1:15014/metrics": EOF`),
+		},
+		{
+			name: "connection reset",
+			err:  errors.New("read tcp 10.0.0.2:40000->10.0.0.1:15014: read: connection reset by peer"),
+		},
+		{
+			name: "tls error",
+			err:  errors.New("remote error: tls: handshake failure"),
+		},
+		{
+			name:    "dns error",
+			err:     errors.New("dial tcp: lookup foo.bar: no such host"),
+			wantErr: true,
+		},
+		{
+			name:    "timeout",
+			err:     errors.New("context deadline exceeded"),
+			wantErr: true,
+		},
+		{
+			name: "forbidden",
+			rs:   echo.Responses{{Code: "403"}},
+		},
+		{
+			name:    "ok",
+			rs:      echo.Responses{{Code: "200"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Rejected().Check(tt.rs, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConnectionReset(t *testing.T) {
+	cases := []struct {
+		name    string
+		rs      echo.Responses
+		err     error
+		wantErr bool
+	}{
+		{
+			name: "tcp reset",
+			err:  errors.New("read tcp 10.0.0.2:40000->10.0.0.1:8080: read: connection reset by peer"),
+		},
+		{
+			name: "grpc reset",
+			err:  errors.New("rpc error: code = Internal desc = stream terminated by RST_STREAM with error code: INTERNAL_ERROR"),
+		},
+		{
+			name: "http2 reset",
+			err:  errors.New("stream error: stream ID 1; INTERNAL_ERROR; received from peer"),
+		},
+		{
+			name: "envoy reported reset",
+			rs: echo.Responses{{
+				Code:       "503",
+				RawContent: "upstream connect error or disconnect/reset before headers. reset reason: connection termination",
+			}},
+		},
+		{
+			name:    "closed connection",
+			err:     errors.New(`Get "http://10.0.0.1:8080/reset": EOF`),
+			wantErr: true,
+		},
+		{
+			name:    "unrelated stream error",
+			err:     errors.New("stream error: stream ID 1; PROTOCOL_ERROR"),
+			wantErr: true,
+		},
+		{
+			name:    "plain 503",
+			rs:      echo.Responses{{Code: "503", RawContent: "no healthy upstream"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ConnectionReset().Check(tt.rs, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRetried(t *testing.T) {
+	attempts := func(n string) echo.Response {
+		return echo.Response{RequestHeaders: http.Header{"X-Envoy-Attempt-Count": {n}}}
+	}
+	cases := []struct {
+		name    string
+		rs      echo.Responses
+		min     int
+		wantErr bool
+	}{
+		{
+			name: "one of several requests retried",
+			rs:   echo.Responses{attempts("1"), attempts("2"), attempts("1")},
+			min:  1,
+		},
+		{
+			name: "retries summed across requests",
+			rs:   echo.Responses{attempts("2"), attempts("3")},
+			min:  3,
+		},
+		{
+			name:    "no request retried",
+			rs:      echo.Responses{attempts("1"), attempts("1")},
+			min:     1,
+			wantErr: true,
+		},
+		{
+			name:    "missing header",
+			rs:      echo.Responses{attempts("2"), {}},
+			min:     1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Retried(tt.min).Check(tt.rs, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
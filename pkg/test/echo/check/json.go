@@ -0,0 +1,112 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+
+	"istio.io/istio/pkg/test/echo"
+)
+
+// JSONBody parses the body of each response as JSON, and checks that the field at the given path equals
+// expected. The path is a dot-separated list of object keys and array indices, e.g. "items.0.name". An empty
+// path refers to the whole body. Expected is compared as if it had been encoded to and decoded from JSON, so
+// that e.g. numbers of any type can be used.
+func JSONBody(path string, expected interface{}) Checker {
+	return Each(func(r echo.Response) error {
+		var body interface{}
+		if err := json.Unmarshal([]byte(responseBody(r)), &body); err != nil {
+			return fmt.Errorf("failed to parse body as JSON: %v", err)
+		}
+		got, err := jsonField(body, path)
+		if err != nil {
+			return err
+		}
+		want, err := normalizeJSON(expected)
+		if err != nil {
+			return fmt.Errorf("failed to encode expected value %v: %v", expected, err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			return fmt.Errorf("unexpected value for JSON field %q (-want +got):\n%s", path, diff)
+		}
+		return nil
+	})
+}
+
+// responseBody returns the body of the response, as written line by line by the forwarder.
+func responseBody(r echo.Response) string {
+	var lines []string
+	for _, l := range strings.Split(r.RawContent, "\n") {
+		if idx := strings.Index(l, "body] "); idx >= 0 {
+			lines = append(lines, l[idx+len("body] "):])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func jsonField(body interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return body, nil
+	}
+	cur := body
+	segments := strings.Split(path, ".")
+	for i, key := range segments {
+		parent := strings.Join(segments[:i], ".")
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("JSON field %q not found; %q has keys %v", path, parent, keys(v))
+			}
+			cur = next
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, fmt.Errorf("JSON field %q not found; %q is an array of length %d", path, parent, len(v))
+			}
+			cur = v[idx]
+		default:
+			return nil, fmt.Errorf("JSON field %q not found; %q is %v, not an object or array", path, parent, v)
+		}
+	}
+	return cur, nil
+}
+
+func normalizeJSON(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func keys(m map[string]interface{}) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -78,3 +78,7 @@ type Port struct {
 type PortList []*Port
 
 var ServerFirstMagicString = "server-first-protocol\n"
+
+// ReadinessProbeUserAgent is the User-Agent sent by the HTTP readiness probe of echo deployments. Requests with
+// this User-Agent are not counted in the echo request metrics.
+const ReadinessProbeUserAgent = "istio-echo-readiness-probe"
@@ -33,6 +33,10 @@ var DefaultRequestTimeout = 5 * time.Second
 const (
 	ConnectionTimeout = 2 * time.Second
 	DefaultCount      = 1
+
+	// FailureHeader instructs the echo server to fail a fraction of requests, in the form code:fraction.
+	// For example, "503:0.25" fails exactly one of every four requests carrying the header with a 503.
+	FailureHeader = "X-Echo-Failure"
 )
 
 // FillInDefaults fills in the timeout and count if not specified in the given message.
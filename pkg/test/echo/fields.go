@@ -37,4 +37,10 @@ const (
 	ClusterField        Field = "Cluster"
 	IstioVersionField   Field = "IstioVersion"
 	IPField             Field = "IP" // The Requester’s IP Address.
+	// UpgradeField is the status code of the WebSocket upgrade handshake.
+	UpgradeField Field = "Upgrade"
+	// LatencyField is the round-trip time of a forwarded request, as measured by the forwarding echo.
+	LatencyField Field = "Latency"
+	// StreamMessagesField is the number of messages echoed on a gRPC stream.
+	StreamMessagesField Field = "StreamMessages"
 )
@@ -17,7 +17,9 @@ package echo
 import (
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"istio.io/istio/pkg/test/echo/proto"
 )
@@ -38,6 +40,9 @@ var (
 	methodFieldRegex         = regexp.MustCompile(string(MethodField) + "=(.*)")
 	protocolFieldRegex       = regexp.MustCompile(string(ProtocolField) + "=(.*)")
 	alpnFieldRegex           = regexp.MustCompile(string(AlpnField) + "=(.*)")
+	upgradeFieldRegex        = regexp.MustCompile(string(UpgradeField) + "=(.*)")
+	latencyFieldRegex        = regexp.MustCompile(string(LatencyField) + "=(.*)")
+	streamMessagesFieldRegex = regexp.MustCompile(string(StreamMessagesField) + "=(.*)")
 )
 
 func ParseResponses(req *proto.ForwardEchoRequest, resp *proto.ForwardEchoResponse) Responses {
@@ -121,6 +126,21 @@ func parseResponse(output string) Response {
 		out.IP = match[1]
 	}
 
+	match = upgradeFieldRegex.FindStringSubmatch(output)
+	if match != nil {
+		out.UpgradeCode = match[1]
+	}
+
+	match = latencyFieldRegex.FindStringSubmatch(output)
+	if match != nil {
+		out.Latency, _ = time.ParseDuration(match[1])
+	}
+
+	match = streamMessagesFieldRegex.FindStringSubmatch(output)
+	if match != nil {
+		out.StreamMessages, _ = strconv.Atoi(match[1])
+	}
+
 	out.rawBody = map[string]string{}
 
 	matches := requestHeaderFieldRegex.FindAllStringSubmatch(output, -1)
@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion7
 type EchoTestServiceClient interface {
 	Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error)
 	ForwardEcho(ctx context.Context, in *ForwardEchoRequest, opts ...grpc.CallOption) (*ForwardEchoResponse, error)
+	EchoStream(ctx context.Context, opts ...grpc.CallOption) (EchoTestService_EchoStreamClient, error)
 }
 
 type echoTestServiceClient struct {
@@ -48,12 +49,44 @@ func (c *echoTestServiceClient) ForwardEcho(ctx context.Context, in *ForwardEcho
 	return out, nil
 }
 
+func (c *echoTestServiceClient) EchoStream(ctx context.Context, opts ...grpc.CallOption) (EchoTestService_EchoStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EchoTestService_ServiceDesc.Streams[0], "/proto.EchoTestService/EchoStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &echoTestServiceEchoStreamClient{stream}
+	return x, nil
+}
+
+type EchoTestService_EchoStreamClient interface {
+	Send(*EchoRequest) error
+	Recv() (*EchoResponse, error)
+	grpc.ClientStream
+}
+
+type echoTestServiceEchoStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *echoTestServiceEchoStreamClient) Send(m *EchoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *echoTestServiceEchoStreamClient) Recv() (*EchoResponse, error) {
+	m := new(EchoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // EchoTestServiceServer is the server API for EchoTestService service.
 // All implementations must embed UnimplementedEchoTestServiceServer
 // for forward compatibility
 type EchoTestServiceServer interface {
 	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
 	ForwardEcho(context.Context, *ForwardEchoRequest) (*ForwardEchoResponse, error)
+	EchoStream(EchoTestService_EchoStreamServer) error
 	mustEmbedUnimplementedEchoTestServiceServer()
 }
 
@@ -67,6 +100,9 @@ func (UnimplementedEchoTestServiceServer) Echo(context.Context, *EchoRequest) (*
 func (UnimplementedEchoTestServiceServer) ForwardEcho(context.Context, *ForwardEchoRequest) (*ForwardEchoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForwardEcho not implemented")
 }
+func (UnimplementedEchoTestServiceServer) EchoStream(EchoTestService_EchoStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EchoStream not implemented")
+}
 func (UnimplementedEchoTestServiceServer) mustEmbedUnimplementedEchoTestServiceServer() {}
 
 // UnsafeEchoTestServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -116,6 +152,32 @@ func _EchoTestService_ForwardEcho_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EchoTestService_EchoStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EchoTestServiceServer).EchoStream(&echoTestServiceEchoStreamServer{stream})
+}
+
+type EchoTestService_EchoStreamServer interface {
+	Send(*EchoResponse) error
+	Recv() (*EchoRequest, error)
+	grpc.ServerStream
+}
+
+type echoTestServiceEchoStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *echoTestServiceEchoStreamServer) Send(m *EchoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *echoTestServiceEchoStreamServer) Recv() (*EchoRequest, error) {
+	m := new(EchoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // EchoTestService_ServiceDesc is the grpc.ServiceDesc for EchoTestService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -132,6 +194,13 @@ var EchoTestService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _EchoTestService_ForwardEcho_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EchoStream",
+			Handler:       _EchoTestService_EchoStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "echo.proto",
 }
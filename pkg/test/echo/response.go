@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // HeaderType is a helper enum for retrieving Headers from a Response.
@@ -62,12 +64,28 @@ type Response struct {
 	IstioVersion string
 	// IP is the requester's ip address
 	IP string
+	// UpgradeCode is the status code of the protocol upgrade handshake (for WebSocket)
+	UpgradeCode string
+	// Latency is the round-trip time of the request, as measured by the forwarding echo
+	Latency time.Duration
+	// StreamMessages is the number of messages echoed on the stream (for gRPC streams)
+	StreamMessages int
 	// rawBody gives a map of all key/values in the body of the response.
 	rawBody         map[string]string
 	RequestHeaders  http.Header
 	ResponseHeaders http.Header
 }
 
+// UsedXDSResolver returns true if the request was made using the gRPC xds:/// resolver.
+func (r Response) UsedXDSResolver() bool {
+	return strings.HasPrefix(r.RequestURL, "xds:///")
+}
+
+// Upgraded returns true if the server accepted the WebSocket upgrade with 101 Switching Protocols.
+func (r Response) Upgraded() bool {
+	return r.UpgradeCode == strconv.Itoa(http.StatusSwitchingProtocols)
+}
+
 // Count occurrences of the given text within the body of this response.
 func (r Response) Count(text string) int {
 	return strings.Count(r.RawContent, text)
@@ -19,6 +19,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"math"
 	"math/rand"
 	"net"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -71,7 +73,9 @@ func (s *httpInstance) Start(onReady OnReadyFunc) error {
 	h2s := &http2.Server{}
 	s.server = &http.Server{
 		Handler: h2c.NewHandler(&httpHandler{
-			Config: s.Config,
+			Config:          s.Config,
+			statusRequests:  make(map[string]int),
+			failureRequests: make(map[string]int),
 		}, h2s),
 	}
 
@@ -195,6 +199,12 @@ func (s *httpInstance) Close() error {
 
 type httpHandler struct {
 	Config
+
+	mu sync.Mutex
+	// statusRequests counts the requests received for each /status/[:code] path and query.
+	statusRequests map[string]int
+	// failureRequests counts the requests received for each FailureHeader value.
+	failureRequests map[string]int
 }
 
 // Imagine a pie of different flavors.
@@ -208,10 +218,13 @@ type codeAndSlices struct {
 func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	id := uuid.New()
 	epLog.WithLabels("method", r.Method, "url", r.URL, "host", r.Host, "headers", r.Header, "id", id).Infof("HTTP Request")
-	if h.Port == nil {
-		defer common.Metrics.HTTPRequests.With(common.PortLabel.Value("uds")).Increment()
-	} else {
-		defer common.Metrics.HTTPRequests.With(common.PortLabel.Value(strconv.Itoa(h.Port.Port))).Increment()
+	// Readiness probes are not counted, so that the metrics only reflect the traffic sent by tests.
+	if r.UserAgent() != common.ReadinessProbeUserAgent {
+		if h.Port == nil {
+			defer common.Metrics.HTTPRequests.With(common.PortLabel.Value("uds")).Increment()
+		} else {
+			defer common.Metrics.HTTPRequests.With(common.PortLabel.Value(strconv.Itoa(h.Port.Port))).Increment()
+		}
 	}
 	if !h.IsServerReady() {
 		// Handle readiness probe failure.
@@ -234,6 +247,13 @@ func writeError(out *bytes.Buffer, msg string) {
 }
 
 func (h *httpHandler) echo(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	// If the request has path /reset abruptly reset the connection, rather than responding
+	if r.URL.Path == resetPath {
+		epLog.WithLabels("id", id).Infof("HTTP Reset")
+		resetConnection(w)
+		return
+	}
+
 	body := bytes.Buffer{}
 
 	if err := r.ParseForm(); err != nil {
@@ -242,6 +262,7 @@ func (h *httpHandler) echo(w http.ResponseWriter, r *http.Request, id uuid.UUID)
 
 	// If the request has form ?delay=[:duration] wait for duration
 	// For example, ?delay=10s will cause the response to wait 10s before responding
+	// Similarly, the path /delay/[:ms] will cause the response to wait the given milliseconds
 	if err := delayResponse(r); err != nil {
 		writeError(&body, "error delaying response error: "+err.Error())
 	}
@@ -251,12 +272,30 @@ func (h *httpHandler) echo(w http.ResponseWriter, r *http.Request, id uuid.UUID)
 		writeError(&body, "response headers error: "+err.Error())
 	}
 
+	// If the request has path /status/[:code] return that code, rather than 200
+	// For example, /status/503?after=2 returns 200 for the first 2 requests to the path and 503 afterwards
+	code, matched, err := h.setResponseFromPath(r, w)
+	if err != nil {
+		writeError(&body, "status error: "+err.Error())
+	}
+
+	// If the request has header X-Echo-Failure: code:fraction return that code for exactly that fraction of requests
+	// For example, X-Echo-Failure: 503:0.5 returns 503 for the first request, 200 for the second, and so on
+	if !matched {
+		code, matched, err = h.setResponseFromFailureHeader(r, w)
+		if err != nil {
+			writeError(&body, "failure error: "+err.Error())
+		}
+	}
+
 	// If the request has form ?codes=code[:chance][,code[:chance]]* return those codes, rather than 200
 	// For example, ?codes=500:1,200:1 returns 500 1/2 times and 200 1/2 times
 	// For example, ?codes=500:90,200:10 returns 500 90% of times and 200 10% of times
-	code, err := setResponseFromCodes(r, w)
-	if err != nil {
-		writeError(&body, "codes error: "+err.Error())
+	if !matched {
+		code, err = setResponseFromCodes(r, w)
+		if err != nil {
+			writeError(&body, "codes error: "+err.Error())
+		}
 	}
 
 	h.addResponsePayload(r, &body)
@@ -345,7 +384,41 @@ func (h *httpHandler) addResponsePayload(r *http.Request, body *bytes.Buffer) {
 	}
 }
 
+const resetPath = "/reset"
+
+// resetConnection closes the underlying connection with a TCP RST. Nothing is written first: once part of a
+// response is received, clients report the broken connection as an unexpected EOF rather than a reset.
+func resetConnection(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		// HTTP/2 connections cannot be hijacked. Aborting the handler resets the stream instead.
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		epLog.Warnf("failed to hijack connection for reset: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		// Discard any unsent data on close, which sends a RST rather than a FIN.
+		_ = tcpConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+const delayPathPrefix = "/delay/"
+
 func delayResponse(request *http.Request) error {
+	// A path of form /delay/[:ms] takes precedence over ?delay=[:duration]
+	if strings.HasPrefix(request.URL.Path, delayPathPrefix) {
+		ms, err := strconv.Atoi(strings.TrimPrefix(request.URL.Path, delayPathPrefix))
+		if err != nil || ms < 0 {
+			return fmt.Errorf("invalid delay path %q", request.URL.Path)
+		}
+		time.Sleep(time.Duration(ms) * time.Millisecond)
+		return nil
+	}
+
 	d := request.FormValue("delay")
 	if len(d) == 0 {
 		return nil
@@ -409,6 +482,81 @@ func setResponseFromCodes(request *http.Request, response http.ResponseWriter) (
 	return responseCode, nil
 }
 
+const statusPathPrefix = "/status/"
+
+// setResponseFromPath writes the status code requested by a path of form /status/[:code]. If the request
+// has form ?after=[:count], the first count requests to the path and query are answered with 200 instead.
+// Returns false if the path does not request a status code.
+func (h *httpHandler) setResponseFromPath(request *http.Request, response http.ResponseWriter) (int, bool, error) {
+	if !strings.HasPrefix(request.URL.Path, statusPathPrefix) {
+		return 0, false, nil
+	}
+
+	code, err := strconv.Atoi(strings.TrimPrefix(request.URL.Path, statusPathPrefix))
+	if err != nil || code < http.StatusOK || code >= 600 {
+		response.WriteHeader(http.StatusBadRequest)
+		return http.StatusBadRequest, true, fmt.Errorf("invalid status path %q", request.URL.Path)
+	}
+
+	after := 0
+	if a := request.FormValue("after"); a != "" {
+		after, err = strconv.Atoi(a)
+		if err != nil || after < 0 {
+			response.WriteHeader(http.StatusBadRequest)
+			return http.StatusBadRequest, true, fmt.Errorf("invalid after %q", a)
+		}
+	}
+
+	h.mu.Lock()
+	key := request.URL.RequestURI()
+	h.statusRequests[key]++
+	count := h.statusRequests[key]
+	h.mu.Unlock()
+
+	if count <= after {
+		code = http.StatusOK
+	}
+	response.WriteHeader(code)
+	return code, true, nil
+}
+
+// setResponseFromFailureHeader writes the failure code requested by the FailureHeader for the requested
+// fraction of requests. Unlike ?codes, failures are deterministic: of every n requests carrying the same
+// header value, exactly ceil(n*fraction) fail. Returns false if the header is not set.
+func (h *httpHandler) setResponseFromFailureHeader(request *http.Request, response http.ResponseWriter) (int, bool, error) {
+	v := request.Header.Get(common.FailureHeader)
+	if v == "" {
+		return 0, false, nil
+	}
+
+	parts := strings.Split(v, ":")
+	if len(parts) != 2 {
+		response.WriteHeader(http.StatusBadRequest)
+		return http.StatusBadRequest, true, fmt.Errorf("invalid %q (want code:fraction)", v)
+	}
+	code, err := strconv.Atoi(parts[0])
+	if err != nil || code < http.StatusOK || code >= 600 {
+		response.WriteHeader(http.StatusBadRequest)
+		return http.StatusBadRequest, true, fmt.Errorf("invalid HTTP response code %q", parts[0])
+	}
+	fraction, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || fraction < 0 || fraction > 1 {
+		response.WriteHeader(http.StatusBadRequest)
+		return http.StatusBadRequest, true, fmt.Errorf("invalid fraction %q", parts[1])
+	}
+
+	h.mu.Lock()
+	h.failureRequests[v]++
+	n := float64(h.failureRequests[v])
+	h.mu.Unlock()
+
+	if math.Ceil(n*fraction) <= math.Ceil((n-1)*fraction) {
+		code = http.StatusOK
+	}
+	response.WriteHeader(code)
+	return code, true, nil
+}
+
 // codes must be comma-separated HTTP response code, colon, positive integer
 func validateCodes(codestrings string) ([]codeAndSlices, error) {
 	if codestrings == "" {
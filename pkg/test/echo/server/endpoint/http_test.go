@@ -0,0 +1,119 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"istio.io/istio/pkg/test/echo/common"
+)
+
+func newTestHTTPHandler() *httpHandler {
+	return &httpHandler{
+		Config: Config{
+			IsServerReady: func() bool { return true },
+		},
+		statusRequests:  make(map[string]int),
+		failureRequests: make(map[string]int),
+	}
+}
+
+func TestResetPath(t *testing.T) {
+	srv := httptest.NewServer(newTestHTTPHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + resetPath)
+	if err == nil {
+		_ = resp.Body.Close()
+		t.Fatalf("expected connection to be reset, got response code %d", resp.StatusCode)
+	}
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("expected connection reset, got: %v", err)
+	}
+}
+
+func TestStatusPathAfter(t *testing.T) {
+	h := newTestHTTPHandler()
+	get := func(url string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec.Code
+	}
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusServiceUnavailable, http.StatusServiceUnavailable} {
+		if got := get("/status/503?after=2"); got != want {
+			t.Fatalf("request %d: expected code %d, got %d", i, want, got)
+		}
+	}
+	// A different query is counted separately, even though the path is the same.
+	if got := get("/status/503?after=1&test=other"); got != http.StatusOK {
+		t.Fatalf("expected first request with a new query to return %d, got %d", http.StatusOK, got)
+	}
+	if got := get("/status/503"); got != http.StatusServiceUnavailable {
+		t.Fatalf("expected code %d without after, got %d", http.StatusServiceUnavailable, got)
+	}
+}
+
+func TestFailureHeader(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   []int
+	}{
+		{
+			name:   "half",
+			header: "503:0.5",
+			want:   []int{503, 200, 503, 200},
+		},
+		{
+			name:   "quarter",
+			header: "500:0.25",
+			want:   []int{500, 200, 200, 200, 500},
+		},
+		{
+			name:   "all",
+			header: "503:1",
+			want:   []int{503, 503, 503},
+		},
+		{
+			name:   "none",
+			header: "503:0",
+			want:   []int{200, 200},
+		},
+		{
+			name:   "invalid",
+			header: "503",
+			want:   []int{400},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHTTPHandler()
+			for i, want := range tt.want {
+				rec := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set(common.FailureHeader, tt.header)
+				h.ServeHTTP(rec, req)
+				if rec.Code != want {
+					t.Fatalf("request %d: expected code %d, got %d", i, want, rec.Code)
+				}
+			}
+		})
+	}
+}
@@ -18,12 +18,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 
+	"istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/echo/proto"
 )
 
@@ -54,6 +56,9 @@ func (c *grpcProtocol) makeRequest(ctx context.Context, req *request) (string, e
 	grpcReq := &proto.EchoRequest{
 		Message: req.Message,
 	}
+	if req.StreamMessages > 0 {
+		return c.makeStreamRequest(ctx, req, grpcReq)
+	}
 	outBuffer.WriteString(fmt.Sprintf("[%d] grpcecho.Echo(%v)\n", req.RequestID, req))
 
 	resp, err := c.client.Echo(ctx, grpcReq)
@@ -72,6 +77,46 @@ func (c *grpcProtocol) makeRequest(ctx context.Context, req *request) (string, e
 	return outBuffer.String(), nil
 }
 
+// makeStreamRequest sends req.StreamMessages messages on a single EchoStream, and reports how many of them were
+// echoed back before the stream was closed.
+func (c *grpcProtocol) makeStreamRequest(ctx context.Context, req *request, grpcReq *proto.EchoRequest) (string, error) {
+	var outBuffer bytes.Buffer
+	outBuffer.WriteString(fmt.Sprintf("[%d] grpcecho.EchoStream(%v)\n", req.RequestID, req))
+
+	echoed := 0
+	// Always report how many messages made it through, even when the stream fails part way.
+	result := func(err error) (string, error) {
+		outBuffer.WriteString(fmt.Sprintf("[%d] %s=%d\n", req.RequestID, echo.StreamMessagesField, echoed))
+		return outBuffer.String(), err
+	}
+
+	stream, err := c.client.EchoStream(ctx)
+	if err != nil {
+		return result(err)
+	}
+	for ; echoed < req.StreamMessages; echoed++ {
+		if err := stream.Send(grpcReq); err != nil {
+			return result(fmt.Errorf("stream closed after %d/%d messages: %v", echoed, req.StreamMessages, err))
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return result(fmt.Errorf("stream closed after %d/%d messages: %v", echoed, req.StreamMessages, err))
+		}
+		for _, line := range strings.Split(resp.GetMessage(), "\n") {
+			if line != "" {
+				outBuffer.WriteString(fmt.Sprintf("[%d body] %s\n", req.RequestID, line))
+			}
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return result(err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		return result(fmt.Errorf("expected stream to end after %d messages, got %v", echoed, err))
+	}
+	return result(nil)
+}
+
 func (c *grpcProtocol) Close() error {
 	return c.conn.Close()
 }
@@ -26,6 +26,7 @@ import (
 	"golang.org/x/sync/semaphore"
 	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
 
+	"istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/echo/common"
 	"istio.io/istio/pkg/test/echo/proto"
 )
@@ -65,6 +66,8 @@ type Instance struct {
 	// Method for the request. Only valid for HTTP
 	method           string
 	expectedResponse *wrappers.StringValue
+	holdOpen         time.Duration
+	streamMessages   int
 }
 
 // New creates a new forwarder Instance.
@@ -87,6 +90,8 @@ func New(cfg Config) (*Instance, error) {
 		header:           common.GetHeaders(cfg.Request),
 		message:          cfg.Request.Message,
 		expectedResponse: cfg.Request.ExpectedResponse,
+		holdOpen:         common.MicrosToDuration(cfg.Request.HoldOpenMicros),
+		streamMessages:   int(cfg.Request.StreamMessages),
 	}, nil
 }
 
@@ -123,6 +128,8 @@ func (i *Instance) Run(ctx context.Context) (*proto.ForwardEchoResponse, error)
 			Timeout:          i.timeout,
 			ServerFirst:      i.serverFirst,
 			Method:           i.method,
+			HoldOpen:         i.holdOpen,
+			StreamMessages:   i.streamMessages,
 		}
 
 		if throttle != nil {
@@ -144,6 +151,7 @@ func (i *Instance) Run(ctx context.Context) (*proto.ForwardEchoResponse, error)
 			if err != nil {
 				return err
 			}
+			resp += fmt.Sprintf("[%d] %s=%v\n", r.RequestID, echo.LatencyField, rt)
 			responsesMu.Lock()
 			responses[r.RequestID] = resp
 			responseTimes[r.RequestID] = rt
@@ -55,6 +55,8 @@ type request struct {
 	Timeout          time.Duration
 	ServerFirst      bool
 	Method           string
+	HoldOpen         time.Duration
+	StreamMessages   int
 }
 
 type protocol interface {
@@ -23,6 +23,7 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"time"
 
 	"istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/echo/common"
@@ -115,9 +116,30 @@ func (c *tcpProtocol) makeRequest(ctx context.Context, req *request) (string, er
 	if !strings.Contains(msg, expected) {
 		return msg, fmt.Errorf("expect to recv message with %s, got %s. Return EOF", expected, msg)
 	}
+	if req.HoldOpen > 0 {
+		if err := holdOpen(conn, req.HoldOpen); err != nil {
+			return msg, err
+		}
+	}
 	return msg, nil
 }
 
+// holdOpen keeps the connection open for the given duration, returning an error if the peer closes it first.
+func holdOpen(conn net.Conn, d time.Duration) error {
+	if err := conn.SetReadDeadline(time.Now().Add(d)); err != nil {
+		return err
+	}
+	buf := make([]byte, 1024)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return nil
+			}
+			return fmt.Errorf("connection closed while held open for %v: %v", d, err)
+		}
+	}
+}
+
 func (c *tcpProtocol) Close() error {
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/gorilla/websocket"
 
+	"istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/echo/common"
 )
 
@@ -46,7 +47,10 @@ func (c *websocketProtocol) makeRequest(ctx context.Context, req *request) (stri
 		outBuffer.WriteString(fmt.Sprintf("[%d] Echo=%s\n", req.RequestID, req.Message))
 	}
 
-	conn, _, err := c.dialer.Dial(req.URL, wsReq)
+	conn, resp, err := c.dialer.Dial(req.URL, wsReq)
+	if resp != nil {
+		outBuffer.WriteString(fmt.Sprintf("[%d] %s=%d\n", req.RequestID, echo.UpgradeField, resp.StatusCode))
+	}
 	if err != nil {
 		// timeout or bad handshake
 		return outBuffer.String(), err
@@ -73,12 +77,12 @@ func (c *websocketProtocol) makeRequest(ctx context.Context, req *request) (stri
 		return outBuffer.String(), err
 	}
 
-	_, resp, err := conn.ReadMessage()
+	_, body, err := conn.ReadMessage()
 	if err != nil {
 		return outBuffer.String(), err
 	}
 
-	for _, line := range strings.Split(string(resp), "\n") {
+	for _, line := range strings.Split(string(body), "\n") {
 		if line != "" {
 			outBuffer.WriteString(fmt.Sprintf("[%d body] %s\n", req.RequestID, line))
 		}
@@ -22,6 +22,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 
 	"istio.io/istio/pkg/test/env"
+	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/features"
 	"istio.io/istio/pkg/test/framework/label"
 	"istio.io/istio/pkg/test/framework/resource"
@@ -249,6 +250,10 @@ func (t *testAnalyzer) RunParallel(fn func(ctx TestContext)) {
 	t.Run(fn)
 }
 
+func (t *testAnalyzer) PerCluster(_ func(ctx TestContext, c cluster.Cluster)) {
+	t.Run(nil)
+}
+
 func (t *testAnalyzer) track() {
 	analysis.addTest(t.goTest.Name(), &testAnalysis{
 		SkipReason:       t.skip,
@@ -46,6 +46,8 @@ var (
 	SidecarIncludeOutboundIPRanges = workloadAnnotation(annotation.SidecarTrafficIncludeOutboundIPRanges.Name, "")
 	SidecarProxyConfig             = workloadAnnotation(annotation.ProxyConfig.Name, "")
 	SidecarInjectTemplates         = workloadAnnotation(annotation.InjectTemplates.Name, "")
+	SidecarProxyImage              = workloadAnnotation(annotation.SidecarProxyImage.Name, "")
+	SidecarExtraStatTags           = workloadAnnotation(annotation.SidecarExtraStatTags.Name, "")
 )
 
 type AnnotationValue struct {
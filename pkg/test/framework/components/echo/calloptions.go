@@ -55,6 +55,17 @@ type HTTP struct {
 
 	// HTTProxy used for making ingress echo call via proxy
 	HTTPProxy string
+
+	// ServerDelay instructs the echo server to wait for the given duration before responding.
+	// Ignored if not positive.
+	ServerDelay time.Duration
+
+	// FailureRate instructs the echo server to fail exactly this fraction of requests with FailureCode.
+	// Must be in the range [0, 1]. Ignored if not positive.
+	FailureRate float64
+
+	// FailureCode returned by the echo server for failed requests. Defaults to 503.
+	FailureCode int
 }
 
 // TLS settings
@@ -66,6 +77,11 @@ type TLS struct {
 	// Use the custom certificates file to make the call.
 	CertFile, KeyFile, CaCertFile string
 
+	// CertProvider, if set, is invoked before each request of the call to get the client certificate and key,
+	// which allows tests to rotate the certificate between the requests of a call with Count > 1. It takes
+	// precedence over Cert/Key and CertFile/KeyFile. The requests are then forwarded one at a time.
+	CertProvider func() (cert, key string)
+
 	// Skip verify peer's certificate.
 	InsecureSkipVerify bool
 
@@ -80,12 +96,34 @@ type Retry struct {
 
 	// Options to be used when retrying. If not specified, defaults will be used.
 	Options []retry.Option
+
+	// MaxAttempts is the maximum number of times each forwarded request is sent when it fails with a
+	// connection error. Unlike Options, these retries happen before the Check is applied, so check failures
+	// are never retried at this level. If zero, the limit is set by Budget alone.
+	MaxAttempts int
+
+	// Budget is the maximum time spent retrying forwarded requests that fail with a connection error. If zero,
+	// the limit is set by MaxAttempts alone. If both are zero, forwarded requests are not retried.
+	Budget time.Duration
 }
 
 // TCP settings
 type TCP struct {
 	// ExpectedResponse asserts this is in the response for TCP requests.
 	ExpectedResponse *wrappers.StringValue
+
+	// HoldOpen keeps each TCP connection open for the given duration after the response is received. The call
+	// fails if the connection is closed by the peer in the meantime, such as by an idle timeout. Timeout must be
+	// larger than HoldOpen.
+	HoldOpen time.Duration
+}
+
+// GRPC settings
+type GRPC struct {
+	// StreamMessages, if non-zero, makes each request open a bidirectional stream and send this many messages
+	// on it, each of which is echoed back, instead of making a unary call. The number of messages echoed before
+	// the stream closed is reported in the StreamMessages field of the response.
+	StreamMessages int
 }
 
 // CallOptions defines options for calling a Endpoint.
@@ -123,6 +161,9 @@ type CallOptions struct {
 	// TCP settings.
 	TCP TCP
 
+	// GRPC settings.
+	GRPC GRPC
+
 	// TLS settings.
 	TLS TLS
 
@@ -206,7 +247,12 @@ func (o *CallOptions) FillDefaults() error {
 				}
 			}
 			if !found {
-				return fmt.Errorf("callOptions: no port named %s available in Target Instance", o.PortName)
+				var available []string
+				for _, port := range targetPorts {
+					available = append(available, port.Name)
+				}
+				return fmt.Errorf("callOptions: no such port %q in Target Instance %s, available ports: %v",
+					o.PortName, o.Target.Config().Service, available)
 			}
 		}
 	} else if o.Scheme == scheme.DNS {
@@ -244,6 +290,17 @@ func (o *CallOptions) FillDefaults() error {
 		o.HTTP.Headers.Set(headers.Host, h)
 	}
 
+	if o.HTTP.FailureRate > 0 {
+		if o.HTTP.FailureRate > 1 {
+			return fmt.Errorf("callOptions: invalid FailureRate %v", o.HTTP.FailureRate)
+		}
+		code := o.HTTP.FailureCode
+		if code == 0 {
+			code = http.StatusServiceUnavailable
+		}
+		o.HTTP.Headers.Set(common.FailureHeader, fmt.Sprintf("%d:%v", code, o.HTTP.FailureRate))
+	}
+
 	if o.Timeout <= 0 {
 		o.Timeout = common.DefaultRequestTimeout
 	}
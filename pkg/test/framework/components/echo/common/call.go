@@ -16,12 +16,18 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	golangproto "google.golang.org/protobuf/proto"
+
 	echoclient "istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/echo/common"
 	"istio.io/istio/pkg/test/echo/common/scheme"
@@ -52,6 +58,9 @@ func callInternal(srcName string, opts *echo.CallOptions, send sendFunc) (echocl
 		targetURL = fmt.Sprintf("%s:///%s", string(opts.Scheme), addressAndPort)
 	default:
 		targetURL = fmt.Sprintf("%s://%s%s", string(opts.Scheme), addressAndPort, opts.HTTP.Path)
+		if opts.HTTP.ServerDelay > 0 {
+			targetURL = addQueryParam(targetURL, "delay", opts.HTTP.ServerDelay.String())
+		}
 	}
 
 	// Copy all the headers.
@@ -64,6 +73,8 @@ func callInternal(srcName string, opts *echo.CallOptions, send sendFunc) (echocl
 		TimeoutMicros:      common.DurationToMicros(opts.Timeout),
 		Message:            opts.Message,
 		ExpectedResponse:   opts.TCP.ExpectedResponse,
+		HoldOpenMicros:     common.DurationToMicros(opts.TCP.HoldOpen),
+		StreamMessages:     int32(opts.GRPC.StreamMessages),
 		Http2:              opts.HTTP.HTTP2,
 		Http3:              opts.HTTP.HTTP3,
 		Method:             opts.HTTP.Method,
@@ -84,6 +95,10 @@ func callInternal(srcName string, opts *echo.CallOptions, send sendFunc) (echocl
 		}
 	}
 
+	if opts.TLS.CertProvider != nil {
+		send = sendWithCertProvider(send, opts.TLS.CertProvider)
+	}
+
 	var responses echoclient.Responses
 	sendAndValidate := func() error {
 		var err error
@@ -121,6 +136,85 @@ func callInternal(srcName string, opts *echo.CallOptions, send sendFunc) (echocl
 	return responses, formatError(err)
 }
 
+// sendWithCertProvider splits the request into one request per count, each using the certificate and key
+// returned by provider at the time it is sent.
+func sendWithCertProvider(send sendFunc, provider func() (cert, key string)) sendFunc {
+	return func(req *proto.ForwardEchoRequest) (echoclient.Responses, error) {
+		out := make(echoclient.Responses, 0, req.Count)
+		for i := int32(0); i < req.Count; i++ {
+			r := golangproto.Clone(req).(*proto.ForwardEchoRequest)
+			r.Count = 1
+			r.Cert, r.Key = provider()
+			r.CertFile, r.KeyFile = "", ""
+			resps, err := send(r)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, resps...)
+		}
+		return out, nil
+	}
+}
+
+// forwardRetryDelay is the delay between attempts of forwardWithRetry.
+const forwardRetryDelay = 100 * time.Millisecond
+
+// clientConnectionError is returned when no client to the echo app of the source workload could be created.
+type clientConnectionError struct {
+	error
+}
+
+// isClientConnectionError returns whether err occurred while reaching the echo app of the source workload.
+// Errors forwarding the request from the app to the target are reported by the app with a status other than
+// Unavailable, and are left to the checks of the call.
+func isClientConnectionError(err error) bool {
+	var connErr clientConnectionError
+	if errors.As(err, &connErr) {
+		return true
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
+// forwardWithRetry sends the request through a client from clientProvider, retrying client connection
+// failures according to the MaxAttempts and Budget of r. Any other error is returned right away.
+func forwardWithRetry(srcName string, clientProvider EchoClientProvider, req *proto.ForwardEchoRequest,
+	r echo.Retry) (echoclient.Responses, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		resps, err := func() (echoclient.Responses, error) {
+			c, err := clientProvider()
+			if err != nil {
+				return nil, clientConnectionError{err}
+			}
+			return c.ForwardEcho(context.Background(), req)
+		}()
+		if err == nil {
+			return resps, nil
+		}
+		if !isClientConnectionError(err) {
+			return nil, err
+		}
+		exhausted := (r.MaxAttempts == 0 && r.Budget == 0) ||
+			(r.MaxAttempts > 0 && attempt >= r.MaxAttempts) ||
+			(r.Budget > 0 && time.Since(start) >= r.Budget)
+		if exhausted {
+			return nil, err
+		}
+		scopes.Framework.Infof("forwarding request from %s to %s failed on attempt %d, retrying: %v",
+			srcName, req.Url, attempt, err)
+		time.Sleep(forwardRetryDelay)
+	}
+}
+
+// addQueryParam appends the given key/value to the query of the target URL.
+func addQueryParam(targetURL, key, value string) string {
+	sep := "?"
+	if strings.Contains(targetURL, "?") {
+		sep = "&"
+	}
+	return targetURL + sep + key + "=" + url.QueryEscape(value)
+}
+
 func CallEcho(opts *echo.CallOptions) (echoclient.Responses, error) {
 	send := func(req *proto.ForwardEchoRequest) (echoclient.Responses, error) {
 		instance, err := forwarder.New(forwarder.Config{
@@ -149,11 +243,7 @@ type EchoClientProvider func() (*echoclient.Client, error)
 
 func ForwardEcho(srcName string, clientProvider EchoClientProvider, opts *echo.CallOptions) (echoclient.Responses, error) {
 	res, err := callInternal(srcName, opts, func(req *proto.ForwardEchoRequest) (echoclient.Responses, error) {
-		c, err := clientProvider()
-		if err != nil {
-			return nil, err
-		}
-		return c.ForwardEcho(context.Background(), req)
+		return forwardWithRetry(srcName, clientProvider, req, opts.Retry)
 	})
 	if err != nil {
 		if opts.Port != nil {
@@ -0,0 +1,80 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	echoclient "istio.io/istio/pkg/test/echo"
+	"istio.io/istio/pkg/test/echo/proto"
+	"istio.io/istio/pkg/test/framework/components/echo"
+)
+
+func TestIsClientConnectionError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "client creation failed",
+			err:  clientConnectionError{errors.New("failed to dial")},
+			want: true,
+		},
+		{
+			name: "source workload unavailable",
+			err:  status.Error(codes.Unavailable, "connection refused"),
+			want: true,
+		},
+		{
+			name: "forwarded request failed",
+			err:  status.Error(codes.Unknown, "1 error occurred: connection refused"),
+		},
+		{
+			name: "forwarded request timed out",
+			err:  status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+		},
+		{
+			name: "plain error",
+			err:  errors.New("unexpected"),
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isClientConnectionError(tt.err); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestForwardWithRetry(t *testing.T) {
+	attempts := 0
+	provider := func() (*echoclient.Client, error) {
+		attempts++
+		return nil, errors.New("failed to dial")
+	}
+	_, err := forwardWithRetry("a", provider, &proto.ForwardEchoRequest{}, echo.Retry{MaxAttempts: 3})
+	if !isClientConnectionError(err) {
+		t.Fatalf("expected a client connection error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"istio.io/istio/pkg/test"
+	echoclient "istio.io/istio/pkg/test/echo"
+	"istio.io/istio/pkg/test/framework/components/echo"
+)
+
+var _ echo.Caller = &LocalCaller{}
+
+// LocalCaller is an echo.Caller that originates calls from the test process itself, rather than from a
+// workload deployed in the mesh. This is useful for validating externally-observable behavior, such as
+// calling an ingress load balancer address. Since the test process is typically outside the cluster,
+// calls without a Target must provide the Address, Port and Scheme or Protocol.
+type LocalCaller struct{}
+
+// NewLocalCaller creates a new LocalCaller.
+func NewLocalCaller() *LocalCaller {
+	return &LocalCaller{}
+}
+
+func (c *LocalCaller) Call(opts echo.CallOptions) (echoclient.Responses, error) {
+	opts = opts.DeepCopy()
+	return CallEcho(&opts)
+}
+
+func (c *LocalCaller) CallOrFail(t test.Failer, opts echo.CallOptions) echoclient.Responses {
+	t.Helper()
+	r, err := c.Call(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -23,8 +23,10 @@ import (
 	"github.com/mitchellh/copystructure"
 	"gopkg.in/yaml.v3"
 
+	clusterid "istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/echo/common"
 	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/components/namespace"
@@ -89,6 +91,11 @@ type Config struct {
 	// for the deployment.
 	ServiceAccount bool
 
+	// ServiceAccountName (k8s only) is the name of the service account to create and use for the deployment,
+	// instead of the service name. Setting it implies ServiceAccount. Services may share a service account,
+	// and therefore an identity, by using the same name.
+	ServiceAccountName string
+
 	// Ports for this application. Port numbers may or may not be used, depending
 	// on the implementation.
 	Ports []Port
@@ -110,6 +117,13 @@ type Config struct {
 	// ReadinessGRPCPort if set, use this port for the GRPC readiness probe (instead of using a HTTP probe).
 	ReadinessGRPCPort string
 
+	// ReadinessProbePath is the path requested by the HTTP readiness probe. Defaults to "/".
+	ReadinessProbePath string
+
+	// ReadinessProbe (k8s only) customizes the readiness probe of the workloads. If nil, the default probe is
+	// used.
+	ReadinessProbe *ProbeConfig
+
 	// Subsets contains the list of Subsets config belonging to this echo
 	// service instance.
 	Subsets []SubsetConfig
@@ -117,6 +131,14 @@ type Config struct {
 	// Cluster to be used in a multicluster environment
 	Cluster cluster.Cluster
 
+	// Clusters restricts deployment to the given subset of clusters in a multicluster environment. It is
+	// ignored if Cluster is set.
+	Clusters cluster.Clusters
+
+	// PerCluster contains overrides, keyed by cluster ID, that are merged over this config when it is
+	// deployed to the given cluster.
+	PerCluster map[clusterid.ID]ConfigOverride
+
 	// TLS settings for echo server
 	TLSSettings *common.TLSSettings
 
@@ -137,6 +159,73 @@ type Config struct {
 	// the CUSTOM authorization policy when the ext-authz server is deployed locally with the application container in
 	// the same pod.
 	IncludeExtAuthz bool
+
+	// Volumes (k8s only) to add to the pods of this instance, so that they can be mounted with VolumeMounts.
+	Volumes []Volume
+
+	// VolumeMounts (k8s only) to add to the app container of this instance.
+	VolumeMounts []VolumeMount
+
+	// ProxyImage (k8s only) overrides the injected sidecar image for all subsets of this instance.
+	ProxyImage string
+
+	// ProxyConcurrency (k8s only) sets the number of worker threads of the sidecar for all subsets of this
+	// instance. It is merged into any proxy config annotation already set on the subset. If 0, the default
+	// concurrency is used.
+	ProxyConcurrency int
+}
+
+// Volume is a ConfigMap or Secret in the namespace of the instance that is made available to its pods.
+type Volume struct {
+	// Name of the volume, referred to by VolumeMount.Name.
+	Name string
+	// ConfigMap to use as the source of the volume.
+	ConfigMap string
+	// Secret to use as the source of the volume, if ConfigMap is not set.
+	Secret string
+}
+
+// VolumeMount mounts a Volume into the app container.
+type VolumeMount struct {
+	// Name of the Volume to mount.
+	Name string
+	// MountPath in the app container.
+	MountPath string
+	// ReadOnly mounts the volume read-only.
+	ReadOnly bool
+}
+
+// ProbeType is the kind of check performed by a probe.
+type ProbeType string
+
+const (
+	// ProbeHTTP sends an HTTP request to the readiness port.
+	ProbeHTTP ProbeType = "HTTP"
+	// ProbeTCP opens a TCP connection to ReadinessTCPPort, or to the TCP health port if not set.
+	ProbeTCP ProbeType = "TCP"
+	// ProbeGRPC runs a gRPC health check against ReadinessGRPCPort, or against the first GRPC port if not set.
+	ProbeGRPC ProbeType = "GRPC"
+)
+
+// ProbeConfig customizes a probe of the echo workloads. Zero fields keep their default.
+type ProbeConfig struct {
+	// Type of the probe. If not set, a TCP or gRPC probe is used if ReadinessTCPPort or ReadinessGRPCPort is
+	// set, respectively, and an HTTP probe otherwise.
+	Type ProbeType
+	// InitialDelay before the first probe. Rounded down to whole seconds. Defaults to 1s.
+	InitialDelay time.Duration
+	// PeriodSeconds between probes. Defaults to 2.
+	PeriodSeconds int
+}
+
+// ConfigOverride contains fields of a Config that may be overridden for a single cluster. Empty fields
+// are not overridden.
+type ConfigOverride struct {
+	// Locality (k8s only) of the app in the cluster.
+	Locality string
+	// Subsets of the app in the cluster. These replace the subsets of the base config after defaults have
+	// been filled, so annotations derived from ProxyImage or ProxyConcurrency are not added to them.
+	Subsets []SubsetConfig
 }
 
 // SubsetConfig is the config for a group of Subsets (e.g. Kubernetes deployment).
@@ -163,6 +252,24 @@ func (c Config) PortByName(name string) *Port {
 	return nil
 }
 
+// Port looks up a given port by name. Returns false if no such port exists.
+func (c Config) Port(name string) (Port, bool) {
+	if p := c.PortByName(name); p != nil {
+		return *p, true
+	}
+	return Port{}, false
+}
+
+// MustPort calls Port and fails the test if no such port exists.
+func (c Config) MustPort(t test.Failer, name string) Port {
+	t.Helper()
+	p, ok := c.Port(name)
+	if !ok {
+		t.Fatalf("no port named %s for echo %s", name, c.Service)
+	}
+	return p
+}
+
 // ClusterLocalFQDN returns the fully qualified domain name for cluster-local host.
 func (c Config) ClusterLocalFQDN() string {
 	out := c.Service
@@ -243,12 +350,35 @@ func (c Config) IsDelta() bool {
 func (c Config) DeepCopy() Config {
 	newc := c
 	newc.Cluster = nil
+	newc.Clusters = nil
 	newc = copyInternal(newc).(Config)
 	newc.Cluster = c.Cluster
+	newc.Clusters = c.Clusters
 	newc.Namespace = c.Namespace
 	return newc
 }
 
+// ForCluster returns a copy of the config with any PerCluster overrides for the given cluster applied.
+func (c Config) ForCluster(id clusterid.ID) Config {
+	o, ok := c.PerCluster[id]
+	if !ok {
+		return c
+	}
+	c = c.DeepCopy()
+	if o.Locality != "" {
+		c.Locality = o.Locality
+	}
+	if len(o.Subsets) > 0 {
+		c.Subsets = append([]SubsetConfig{}, o.Subsets...)
+		for i := range c.Subsets {
+			if c.Subsets[i].Version == "" {
+				c.Subsets[i].Version = c.Version
+			}
+		}
+	}
+	return c
+}
+
 func (c Config) IsExternal() bool {
 	return c.HostHeader() != c.ClusterLocalFQDN()
 }
@@ -290,10 +420,30 @@ func (c *Config) FillDefaults(ctx resource.Context) (err error) {
 		}
 	}
 
+	// Make a copy of the subsets array, since the subset annotations may be modified below.
+	c.Subsets = append([]SubsetConfig{}, c.Subsets...)
 	for i := range c.Subsets {
 		if c.Subsets[i].Version == "" {
 			c.Subsets[i].Version = c.Version
 		}
+		if c.ProxyImage == "" && c.ProxyConcurrency == 0 {
+			continue
+		}
+		annotations := NewAnnotations()
+		for k, v := range c.Subsets[i].Annotations {
+			annotations[k] = v
+		}
+		if c.ProxyImage != "" {
+			annotations.Set(SidecarProxyImage, c.ProxyImage)
+		}
+		if c.ProxyConcurrency > 0 {
+			proxyConfig := strings.TrimRight(annotations.Get(SidecarProxyConfig), "\n")
+			if proxyConfig != "" {
+				proxyConfig += "\n"
+			}
+			annotations.Set(SidecarProxyConfig, fmt.Sprintf("%sconcurrency: %d", proxyConfig, c.ProxyConcurrency))
+		}
+		c.Subsets[i].Annotations = annotations
 	}
 	c.addPortIfMissing(protocol.GRPC)
 	// If no namespace was provided, use the default.
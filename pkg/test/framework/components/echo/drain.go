@@ -0,0 +1,129 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeRetry "k8s.io/client-go/util/retry"
+
+	"istio.io/istio/pkg/test/echo/check"
+	"istio.io/istio/pkg/test/framework/components/cluster"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+// NodeName returns the name of the node running the pod of the given workload of the instance.
+func NodeName(i Instance, w Workload) (string, error) {
+	cfg := i.Config()
+	pod, err := cfg.Cluster.CoreV1().Pods(cfg.Namespace.Name()).Get(context.TODO(), w.PodName(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return pod.Spec.NodeName, nil
+}
+
+// VerifyNodeDrain cordons the node running the first workload of the target and evicts the target's pods
+// from it, waits for the target's workloads to be rescheduled onto other nodes and then checks that calls
+// from the caller using opts succeed. Other pods on the node are left running, so that the drain does not
+// disrupt the control plane or other tests sharing the cluster. The node is uncordoned before returning.
+// The cluster must have another schedulable node.
+func VerifyNodeDrain(from Caller, to Instance, opts CallOptions) (err error) {
+	workloads, err := to.Workloads()
+	if err != nil {
+		return err
+	}
+	node, err := NodeName(to, workloads[0])
+	if err != nil {
+		return err
+	}
+	c := to.Config().Cluster
+	if err := setUnschedulable(c, node, true); err != nil {
+		return fmt.Errorf("failed cordoning node %s: %v", node, err)
+	}
+	defer func() {
+		if uerr := setUnschedulable(c, node, false); uerr != nil && err == nil {
+			err = fmt.Errorf("failed uncordoning node %s: %v", node, uerr)
+		}
+	}()
+	if err := evictFromNode(to, workloads, node); err != nil {
+		return fmt.Errorf("failed draining node %s: %v", node, err)
+	}
+
+	err = retry.UntilSuccess(func() error {
+		current, err := to.Workloads()
+		if err != nil {
+			return err
+		}
+		if len(current) != len(workloads) {
+			return fmt.Errorf("expected %d ready workloads for %s, got %d", len(workloads), to.Config().Service, len(current))
+		}
+		for _, w := range current {
+			n, err := NodeName(to, w)
+			if err != nil {
+				return err
+			}
+			if n == node {
+				return fmt.Errorf("workload %s is still running on drained node %s", w.PodName(), node)
+			}
+		}
+		return nil
+	}, retry.Timeout(2*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	if opts.Check == nil {
+		opts.Check = check.OK()
+	}
+	_, err = from.Call(opts)
+	return err
+}
+
+func setUnschedulable(c cluster.Cluster, node string, unschedulable bool) error {
+	return kubeRetry.RetryOnConflict(kubeRetry.DefaultRetry, func() error {
+		n, err := c.CoreV1().Nodes().Get(context.TODO(), node, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		n.Spec.Unschedulable = unschedulable
+		_, err = c.CoreV1().Nodes().Update(context.TODO(), n, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// evictFromNode evicts the pods of the given workloads of the instance that are running on the node.
+func evictFromNode(i Instance, workloads []Workload, node string) error {
+	cfg := i.Config()
+	for _, w := range workloads {
+		n, err := NodeName(i, w)
+		if err != nil {
+			return err
+		}
+		if n != node {
+			continue
+		}
+		err = cfg.Cluster.CoreV1().Pods(cfg.Namespace.Name()).EvictV1(context.TODO(), &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: w.PodName(), Namespace: cfg.Namespace.Name()},
+		})
+		if err != nil {
+			return fmt.Errorf("failed evicting pod %s/%s: %v", cfg.Namespace.Name(), w.PodName(), err)
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/framework/components/cluster"
+	"istio.io/istio/pkg/test/framework/components/namespace"
 )
 
 // Builder for a group of collaborating Echo Instances. Once built, all Instances in the
@@ -44,9 +45,17 @@ type Builder interface {
 	// TODO rename this to With, and the old method to WithInstance
 	WithConfig(cfg Config) Builder
 
+	// WithConfigInNamespaces behaves like WithConfig, but adds a copy of the config to each of the
+	// given namespaces.
+	WithConfigInNamespaces(cfg Config, namespaces ...namespace.Instance) Builder
+
 	// WithClusters will cause subsequent With or WithConfig calls to be applied to the given clusters.
 	WithClusters(...cluster.Cluster) Builder
 
+	// WithConcurrency limits the number of services that are deployed at the same time by Build. Instances of
+	// the same service are always deployed together. By default, or if n <= 0, there is no limit.
+	WithConcurrency(n int) Builder
+
 	// Build and initialize all Echo Instances. Upon returning, the Instance pointers
 	// are assigned and all Instances are ready to communicate with each other.
 	Build() (Instances, error)
@@ -61,6 +70,26 @@ type Caller interface {
 
 type Callers []Caller
 
+// Checker describes traffic that is sent repeatedly to verify a property over time, such as continuity
+// during an upgrade: each call is made from From with Options, whose Check validates the call.
+type Checker struct {
+	From    Caller
+	Options CallOptions
+}
+
+// Instances returns the instances that take part in the checked traffic: the target of the calls, and the
+// caller if it is an Instance.
+func (c Checker) Instances() Instances {
+	var out Instances
+	if c.Options.Target != nil {
+		out = append(out, c.Options.Target)
+	}
+	if from, ok := c.From.(Instance); ok && from != c.Options.Target {
+		out = append(out, from)
+	}
+	return out
+}
+
 // Instances returns an Instances if all callers are Instance, otherwise returns nil.
 func (c Callers) Instances() Instances {
 	var out Instances
@@ -18,7 +18,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-multierror"
@@ -26,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"istio.io/istio/pilot/pkg/util/sets"
+	clusterid "istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/kube/inject"
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/framework/components/cluster"
@@ -84,12 +84,23 @@ type builder struct {
 	templates map[string]sets.Set
 	// errs contains a multierror for failed validation during With calls
 	errs error
+	// concurrency is the maximum number of services deployed at the same time, or unlimited if <= 0
+	concurrency int
 }
 
 func (b builder) WithConfig(cfg echo.Config) echo.Builder {
 	return b.With(nil, cfg).(builder)
 }
 
+func (b builder) WithConfigInNamespaces(cfg echo.Config, namespaces ...namespace.Instance) echo.Builder {
+	for _, ns := range namespaces {
+		nsCfg := cfg
+		nsCfg.Namespace = ns
+		b = b.WithConfig(nsCfg).(builder)
+	}
+	return b
+}
+
 // With adds a new Echo configuration to the Builder. When a cluster is provided in the Config, it will only be applied
 // to that cluster, otherwise the Config is applied to all WithClusters. Once built, if being built for a single cluster,
 // the instance pointer will be updated to point at the new Instance.
@@ -110,6 +121,8 @@ func (b builder) With(i *echo.Instance, cfg echo.Config) echo.Builder {
 	targetClusters := b.clusters
 	if cfg.Cluster != nil {
 		targetClusters = cluster.Clusters{cfg.Cluster}
+	} else if len(cfg.Clusters) > 0 {
+		targetClusters = cfg.Clusters
 	}
 
 	// If we didn't deploy VMs, but we don't care about VMs, we can ignore this.
@@ -141,7 +154,7 @@ func (b builder) With(i *echo.Instance, cfg echo.Config) echo.Builder {
 			// TODO: should we just panic if a ref is passed in a multi-cluster context?
 			ref = i
 		}
-		perClusterConfig = perClusterConfig.DeepCopy()
+		perClusterConfig = perClusterConfig.ForCluster(clusterid.ID(c.Name())).DeepCopy()
 		k := ec.Kind()
 		perClusterConfig.Cluster = ec
 		b.configs[k] = append(b.configs[k], perClusterConfig)
@@ -163,6 +176,14 @@ func (b builder) WithClusters(clusters ...cluster.Cluster) echo.Builder {
 	return next
 }
 
+// WithConcurrency limits the number of services that are deployed at the same time by Build. If n <= 0,
+// all services are deployed at once.
+func (b builder) WithConcurrency(n int) echo.Builder {
+	next := b
+	next.concurrency = n
+	return next
+}
+
 func (b builder) Build() (out echo.Instances, err error) {
 	return build(b)
 }
@@ -297,41 +318,83 @@ func (b builder) deployServices() error {
 }
 
 func (b builder) deployInstances() (echo.Instances, error) {
-	m := sync.Mutex{}
-	out := echo.Instances{}
+	var sem chan struct{}
+	if b.concurrency > 0 {
+		sem = make(chan struct{}, b.concurrency)
+	}
+	built := map[cluster.Kind]echo.Instances{}
 	errGroup := multierror.Group{}
-	// run the builder func for each kind of config in parallel
+	// run the builder func for each service of each kind of config in parallel, since services do not
+	// depend on each other. The instances of a service are built together, in order.
 	for kind, configs := range b.configs {
 		kind := kind
 		configs := configs
-		errGroup.Go(func() error {
-			buildFunc, err := echo.GetBuilder(kind)
-			if err != nil {
-				return err
-			}
-			instances, err := buildFunc(b.ctx, configs)
-			if err != nil {
-				return err
-			}
-
-			// link reference pointers
-			if err := assignRefs(b.refs[kind], instances); err != nil {
-				return err
-			}
+		buildFunc, err := echo.GetBuilder(kind)
+		if err != nil {
+			return nil, err
+		}
+		// each service writes the instances to its own indices, so no locking is needed.
+		instances := make(echo.Instances, len(configs))
+		built[kind] = instances
+		for _, indices := range groupByService(configs) {
+			indices := indices
+			errGroup.Go(func() error {
+				if sem != nil {
+					sem <- struct{}{}
+					defer func() { <-sem }()
+				}
 
-			// safely merge instances from all kinds of cluster into one list
-			m.Lock()
-			defer m.Unlock()
-			out = append(out, instances...)
-			return nil
-		})
+				serviceConfigs := make([]echo.Config, 0, len(indices))
+				for _, idx := range indices {
+					serviceConfigs = append(serviceConfigs, configs[idx])
+				}
+				serviceInstances, err := buildFunc(b.ctx, serviceConfigs)
+				if err != nil {
+					return err
+				}
+				if len(serviceInstances) != len(indices) {
+					return fmt.Errorf("built %d instances of %s, expected %d",
+						len(serviceInstances), serviceConfigs[0].ClusterLocalFQDN(), len(indices))
+				}
+				for i, idx := range indices {
+					instances[idx] = serviceInstances[i]
+				}
+				return nil
+			})
+		}
 	}
 	if err := errGroup.Wait().ErrorOrNil(); err != nil {
 		return nil, err
 	}
+
+	out := echo.Instances{}
+	for kind, instances := range built {
+		// link reference pointers
+		if err := assignRefs(b.refs[kind], instances); err != nil {
+			return nil, err
+		}
+		out = append(out, instances...)
+	}
 	return out, nil
 }
 
+// groupByService returns the indices of the configs of each service, in the order the services first appear.
+func groupByService(configs []echo.Config) [][]int {
+	var out [][]int
+	groups := map[string]int{}
+	for idx, cfg := range configs {
+		fqdn := cfg.ClusterLocalFQDN()
+		g, ok := groups[fqdn]
+		if !ok {
+			g = len(out)
+			groups[fqdn] = g
+			out = append(out, nil)
+		}
+		out[g] = append(out[g], idx)
+	}
+	return out
+}
+
 func assignRefs(refs []*echo.Instance, instances echo.Instances) error {
 	if len(refs) != len(instances) {
 		return fmt.Errorf("cannot set %d references, only %d instances were built", len(refs), len(instances))
@@ -0,0 +1,57 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echoboot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"istio.io/istio/pkg/test/framework/components/echo"
+)
+
+func TestGroupByService(t *testing.T) {
+	cases := []struct {
+		name    string
+		configs []echo.Config
+		want    [][]int
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:    "distinct services",
+			configs: []echo.Config{{Service: "a"}, {Service: "b"}},
+			want:    [][]int{{0}, {1}},
+		},
+		{
+			name:    "interleaved services",
+			configs: []echo.Config{{Service: "a"}, {Service: "b"}, {Service: "a"}, {Service: "c"}, {Service: "b"}},
+			want:    [][]int{{0, 2}, {1, 4}, {3}},
+		},
+		{
+			name:    "same name in another domain",
+			configs: []echo.Config{{Service: "a"}, {Service: "a", Domain: "example.com"}, {Service: "a"}},
+			want:    [][]int{{0, 2}, {1}},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, groupByService(tt.configs)); diff != "" {
+				t.Fatalf("unexpected groups (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
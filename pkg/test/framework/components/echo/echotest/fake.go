@@ -62,6 +62,18 @@ func (f fakeInstance) CallOrFail(t test.Failer, options echo.CallOptions) echoCl
 	panic("implement me")
 }
 
+func (f fakeInstance) CallFromWorkload(w echo.Workload, options echo.CallOptions) (echoClient.Responses, error) {
+	panic("implement me")
+}
+
+func (f fakeInstance) Logs(container string) (map[string]string, error) {
+	panic("implement me")
+}
+
+func (f fakeInstance) LogsOrFail(t test.Failer, container string) map[string]string {
+	panic("implement me")
+}
+
 func (f fakeInstance) Restart() error {
 	panic("implement me")
 }
@@ -0,0 +1,234 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/istio/pkg/test/echo/check"
+	"istio.io/istio/pkg/test/framework/components/cluster"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+// ScaleInCluster scales the Deployments backing the given instances in the given cluster to the
+// given number of replicas. StatefulSets are not scaled. The number of replicas each Deployment had
+// before scaling is returned, keyed by name, so that it can be restored with RestoreScale.
+func ScaleInCluster(to Instances, c cluster.Cluster, replicas int32) (map[types.NamespacedName]int32, error) {
+	targets := to.Match(InCluster(c))
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no target instances in cluster %s", c.Name())
+	}
+	original := map[types.NamespacedName]int32{}
+	for _, target := range targets {
+		cfg := target.Config()
+		deployments := c.AppsV1().Deployments(cfg.Namespace.Name())
+		list, err := deployments.List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return original, err
+		}
+		for _, d := range list.Items {
+			if d.Spec.Selector == nil || d.Spec.Selector.MatchLabels["app"] != cfg.Service {
+				continue
+			}
+			s, err := deployments.GetScale(context.TODO(), d.Name, metav1.GetOptions{})
+			if err != nil {
+				return original, err
+			}
+			original[types.NamespacedName{Namespace: cfg.Namespace.Name(), Name: d.Name}] = s.Spec.Replicas
+			s.Spec.Replicas = replicas
+			if _, err := deployments.UpdateScale(context.TODO(), d.Name, s, metav1.UpdateOptions{}); err != nil {
+				return original, err
+			}
+		}
+	}
+	return original, nil
+}
+
+// RestoreScale scales each of the Deployments in the given cluster back to the number of replicas
+// returned by ScaleInCluster.
+func RestoreScale(c cluster.Cluster, original map[types.NamespacedName]int32) error {
+	for name, replicas := range original {
+		deployments := c.AppsV1().Deployments(name.Namespace)
+		s, err := deployments.GetScale(context.TODO(), name.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		s.Spec.Replicas = replicas
+		if _, err := deployments.UpdateScale(context.TODO(), name.Name, s, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyFailover makes the target unavailable in the failed cluster by scaling it to zero, then checks that
+// calls from the caller using opts are served by the target's remaining clusters. In addition to opts.Check,
+// every remaining cluster must be reached, so opts.Count should be large enough to cover them. The target is
+// scaled back to its original replicas, and is ready again, before returning.
+func VerifyFailover(from Caller, to Instances, failed cluster.Cluster, opts CallOptions) (err error) {
+	remaining := to.Match(Not(InCluster(failed))).Clusters()
+	if len(remaining) == 0 {
+		return fmt.Errorf("no clusters to fail over to from %s", failed.Name())
+	}
+	targets := to.Match(InCluster(failed))
+	ready := map[string]int{}
+	for _, target := range targets {
+		workloads, err := target.Workloads()
+		if err != nil {
+			return err
+		}
+		ready[target.Config().Service] = len(workloads)
+	}
+
+	original, err := ScaleInCluster(to, failed, 0)
+	defer func() {
+		if serr := restoreFailedCluster(targets, failed, original, ready); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	// Terminating pods may still answer, so wait for them to be gone before sending traffic.
+	if err := retry.UntilSuccess(func() error {
+		for _, target := range targets {
+			cfg := target.Config()
+			pods, err := failed.CoreV1().Pods(cfg.Namespace.Name()).List(context.TODO(), metav1.ListOptions{
+				LabelSelector: "app=" + cfg.Service,
+			})
+			if err != nil {
+				return err
+			}
+			if len(pods.Items) > 0 {
+				return fmt.Errorf("%d pods of %s are still present in cluster %s", len(pods.Items), cfg.Service, failed.Name())
+			}
+		}
+		return nil
+	}, retry.Timeout(2*time.Minute)); err != nil {
+		return err
+	}
+
+	if opts.Check == nil {
+		opts.Check = check.OK()
+	}
+	opts.Check = check.And(opts.Check, check.ReachedClusters(remaining))
+	_, err = from.Call(opts)
+	return err
+}
+
+// restoreFailedCluster restores the replicas of the targets scaled down by VerifyFailover, and waits until each
+// of them has as many ready workloads as it had before.
+func restoreFailedCluster(targets Instances, failed cluster.Cluster, original map[types.NamespacedName]int32, ready map[string]int) error {
+	if err := RestoreScale(failed, original); err != nil {
+		return err
+	}
+	return retry.UntilSuccess(func() error {
+		for _, target := range targets {
+			workloads, err := target.Workloads()
+			if err != nil {
+				return err
+			}
+			if want := ready[target.Config().Service]; len(workloads) < want {
+				return fmt.Errorf("expected %d ready workloads for %s, got %d", want, target.Config().Service, len(workloads))
+			}
+		}
+		return nil
+	}, retry.Timeout(2*time.Minute))
+}
+
+// EvictWorkload deletes the pod of the workload at the given index of the instance, and waits until the
+// Deployment has replaced it with a ready pod. Callers typically run a traffic.Generator while evicting to
+// verify that traffic is rerouted around the deleted endpoint.
+func EvictWorkload(i Instance, index int) error {
+	workloads, err := i.Workloads()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(workloads) {
+		return fmt.Errorf("workload index %d out of range for %s with %d workloads", index, i.Config().Service, len(workloads))
+	}
+	cfg := i.Config()
+	evicted := workloads[index].PodName()
+	if err := deletePod(i, evicted); err != nil {
+		return err
+	}
+
+	return retry.UntilSuccess(func() error {
+		current, err := i.Workloads()
+		if err != nil {
+			return err
+		}
+		if len(current) != len(workloads) {
+			return fmt.Errorf("expected %d ready workloads for %s, got %d", len(workloads), cfg.Service, len(current))
+		}
+		for _, w := range current {
+			if w.PodName() == evicted {
+				return fmt.Errorf("evicted pod %s is still present", evicted)
+			}
+		}
+		return nil
+	}, retry.Timeout(2*time.Minute))
+}
+
+// EndpointRemovalLatency deletes the pod of the workload at the given index of the target, and returns how long it
+// took until the sidecar of the first workload of from no longer lists the deleted endpoint in its clusters.
+func EndpointRemovalLatency(from, to Instance, index int) (time.Duration, error) {
+	workloads, err := to.Workloads()
+	if err != nil {
+		return 0, err
+	}
+	if index < 0 || index >= len(workloads) {
+		return 0, fmt.Errorf("workload index %d out of range for %s with %d workloads", index, to.Config().Service, len(workloads))
+	}
+	removed := workloads[index].Address()
+	start := time.Now()
+	if err := deletePod(to, workloads[index].PodName()); err != nil {
+		return 0, err
+	}
+
+	err = retry.UntilSuccess(func() error {
+		clusters, err := ProxyClusters(from, to.Config().ClusterLocalFQDN())
+		if err != nil {
+			return err
+		}
+		for _, c := range clusters {
+			for _, ep := range c.Endpoints {
+				if host, _, _ := net.SplitHostPort(ep); host == removed {
+					return fmt.Errorf("endpoint %s is still present in cluster %s", ep, c.Name)
+				}
+			}
+		}
+		return nil
+	}, retry.Delay(100*time.Millisecond), retry.Timeout(time.Minute))
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+func deletePod(i Instance, name string) error {
+	cfg := i.Config()
+	if err := cfg.Cluster.CoreV1().Pods(cfg.Namespace.Name()).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed deleting pod %s/%s: %v", cfg.Namespace.Name(), name, err)
+	}
+	return nil
+}
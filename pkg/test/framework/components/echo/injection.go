@@ -0,0 +1,112 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+	"istio.io/istio/pkg/test"
+)
+
+// InjectionStatus returns how the pod of the first workload of the given instance was injected, as recorded in
+// its annotations: the injection templates that were applied, comma separated, and the revision of the
+// injector. If the pod was not injected, injected is false and the other values are empty.
+func InjectionStatus(i Instance) (template, revision string, injected bool, err error) {
+	workloads, err := i.Workloads()
+	if err != nil {
+		return "", "", false, err
+	}
+	if len(workloads) == 0 {
+		return "", "", false, fmt.Errorf("no workloads found for %s", i.Config().Service)
+	}
+	cfg := i.Config()
+	pod, err := cfg.Cluster.CoreV1().Pods(cfg.Namespace.Name()).Get(context.TODO(), workloads[0].PodName(), metav1.GetOptions{})
+	if err != nil {
+		return "", "", false, err
+	}
+	status, f := pod.Annotations[annotation.SidecarStatus.Name]
+	if !f {
+		return "", "", false, nil
+	}
+	var s struct {
+		Revision string `json:"revision"`
+	}
+	if err := json.Unmarshal([]byte(status), &s); err != nil {
+		return "", "", false, fmt.Errorf("failed parsing %s of %s: %v", annotation.SidecarStatus.Name, pod.Name, err)
+	}
+	template = pod.Annotations[annotation.InjectTemplates.Name]
+	if template == "" {
+		// The injector applies the sidecar template unless others are requested.
+		template = "sidecar"
+	}
+	return template, s.Revision, true, nil
+}
+
+// IsInjected returns whether the pod of the first workload of the given instance has a sidecar injected.
+func IsInjected(i Instance) (bool, error) {
+	_, _, injected, err := InjectionStatus(i)
+	return injected, err
+}
+
+// ExpectInjected returns whether the pods of the given instance are expected to be injected, based on the
+// labels of its namespace and the sidecar.istio.io/inject annotation of its first subset. The injector applies
+// the following precedence:
+//   - an "istio-injection: disabled" namespace label disables injection, even if istio.io/rev is set.
+//   - otherwise, injection is enabled by "istio-injection: enabled" or by an istio.io/rev label.
+//   - a "false" pod annotation disables injection in an enabled namespace, but a "true" annotation does not
+//     enable injection in a namespace that is not.
+func ExpectInjected(i Instance) (bool, error) {
+	cfg := i.Config()
+	labels, err := cfg.Namespace.Labels()
+	if err != nil {
+		return false, err
+	}
+	var nsEnabled bool
+	switch labels["istio-injection"] {
+	case "disabled":
+		return false, nil
+	case "enabled":
+		nsEnabled = true
+	default:
+		nsEnabled = labels[label.IoIstioRev.Name] != ""
+	}
+	podEnabled := len(cfg.Subsets) == 0 || cfg.Subsets[0].Annotations == nil ||
+		cfg.Subsets[0].Annotations.GetBool(SidecarInject)
+	return nsEnabled && podEnabled, nil
+}
+
+// CheckInjectionOrFail fails the test if whether the given instance was injected differs from the decision
+// expected by ExpectInjected.
+func CheckInjectionOrFail(t test.Failer, i Instance) {
+	t.Helper()
+	expected, err := ExpectInjected(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	injected, err := IsInjected(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if injected != expected {
+		t.Fatalf("expected injected=%v for %s in namespace %s, got injected=%v",
+			expected, i.Config().Service, i.Config().Namespace.Name(), injected)
+	}
+}
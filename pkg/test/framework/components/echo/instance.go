@@ -16,6 +16,7 @@ package echo
 
 import (
 	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/framework/resource"
 )
 
@@ -35,6 +36,15 @@ type Instance interface {
 	Workloads() ([]Workload, error)
 	WorkloadsOrFail(t test.Failer) []Workload
 
+	// CallFromWorkload makes the call from the given workload of this instance only, rather than from all of
+	// its workloads. The responses have the same shape as those returned by Call.
+	CallFromWorkload(w Workload, opts CallOptions) (echo.Responses, error)
+
+	// Logs returns the logs of the given container (e.g. "app" or "istio-proxy") of each workload, keyed by
+	// the pod name of the workload.
+	Logs(container string) (map[string]string, error)
+	LogsOrFail(t test.Failer, container string) map[string]string
+
 	// Restart restarts the workloads associated with this echo instance
 	Restart() error
 }
@@ -16,6 +16,7 @@ package echo
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 	"strings"
 
@@ -81,6 +82,25 @@ func (i Instances) GetOrFail(t test.Failer, matches Matcher) Instance {
 	return res
 }
 
+// ForCluster returns the single Instance deployed in the given cluster. An error is returned if there is not
+// exactly one, in which case the instances should be narrowed down with Match first.
+func (i Instances) ForCluster(c cluster.Cluster) (Instance, error) {
+	res := i.Match(InCluster(c))
+	if len(res) != 1 {
+		return nil, fmt.Errorf("found %d echo instances in cluster %s, expected exactly 1", len(res), c.StableName())
+	}
+	return res[0], nil
+}
+
+// ForClusterOrFail calls ForCluster and fails the test if it returns an error.
+func (i Instances) ForClusterOrFail(t test.Failer, c cluster.Cluster) Instance {
+	res, err := i.ForCluster(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
 func (i Instances) Contains(instances ...Instance) bool {
 	matches := i.Match(func(instance Instance) bool {
 		for _, ii := range instances {
@@ -21,6 +21,7 @@ import (
 	"net"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -59,7 +60,7 @@ const (
 apiVersion: v1
 kind: ServiceAccount
 metadata:
-  name: {{ .Service }}
+  name: {{ .ServiceAccount }}
 ---
 {{- end }}
 apiVersion: v1
@@ -82,7 +83,14 @@ spec:
 {{- range $i, $p := .Ports }}
   - name: {{ $p.Name }}
     port: {{ $p.ServicePort }}
+{{- if $p.TargetPort }}
+    targetPort: {{ $p.TargetPort }}
+{{- else }}
     targetPort: {{ $p.InstancePort }}
+{{- end }}
+{{- if $p.AppProtocol }}
+    appProtocol: {{ $p.AppProtocol }}
+{{- end }}
 {{- end }}
   selector:
     app: {{ .Service }}
@@ -138,7 +146,7 @@ spec:
 {{- end }}
     spec:
 {{- if $.ServiceAccount }}
-      serviceAccountName: {{ $.Service }}
+      serviceAccountName: {{ $.ServiceAccount }}
 {{- end }}
 {{- if ne $.ImagePullSecretName "" }}
       imagePullSecrets:
@@ -244,19 +252,22 @@ spec:
           value: "true"
 {{- end }}
         readinessProbe:
-{{- if $.ReadinessTCPPort }}
+{{- if $.Readiness.TCPPort }}
           tcpSocket:
-            port: {{ $.ReadinessTCPPort }}
-{{- else if $.ReadinessGRPCPort }}
+            port: {{ $.Readiness.TCPPort }}
+{{- else if $.Readiness.GRPCPort }}
           grpc:
-            port: {{ $.ReadinessGRPCPort }}			
+            port: {{ $.Readiness.GRPCPort }}			
 {{- else }}
           httpGet:
-            path: /
+            path: {{ $.ReadinessProbePath }}
             port: 8080
+            httpHeaders:
+            - name: User-Agent
+              value: {{ $.ReadinessUserAgent }}
 {{- end }}
-          initialDelaySeconds: 1
-          periodSeconds: 2
+          initialDelaySeconds: {{ $.Readiness.InitialDelaySeconds }}
+          periodSeconds: {{ $.Readiness.PeriodSeconds }}
           failureThreshold: 10
         livenessProbe:
           tcpSocket:
@@ -271,11 +282,23 @@ spec:
           periodSeconds: 1
           failureThreshold: 10
 {{- end }}
-{{- if $.TLSSettings }}
+{{- if or $.TLSSettings $.VolumeMounts }}
         volumeMounts:
+{{- if $.TLSSettings }}
         - mountPath: /etc/certs/custom
           name: custom-certs
+{{- end }}
+{{- range $mount := $.VolumeMounts }}
+        - mountPath: {{ $mount.MountPath }}
+          name: {{ $mount.Name }}
+{{- if $mount.ReadOnly }}
+          readOnly: true
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if or $.TLSSettings $.Volumes }}
       volumes:
+{{- if $.TLSSettings }}
 {{- if $.TLSSettings.ProxyProvision }}
       - emptyDir:
           medium: Memory
@@ -285,6 +308,17 @@ spec:
 {{- end }}
         name: custom-certs
 {{- end }}
+{{- range $volume := $.Volumes }}
+      - name: {{ $volume.Name }}
+{{- if $volume.ConfigMap }}
+        configMap:
+          name: {{ $volume.ConfigMap }}
+{{- else }}
+        secret:
+          secretName: {{ $volume.Secret }}
+{{- end }}
+{{- end }}
+{{- end }}
 ---
 {{- end }}
 {{- end }}
@@ -667,6 +701,10 @@ func templateParams(cfg echo.Config, settings *resource.Settings) (map[string]in
 	if err != nil {
 		return nil, err
 	}
+	readiness, err := getReadinessProbe(cfg)
+	if err != nil {
+		return nil, err
+	}
 	params := map[string]interface{}{
 		"ImageHub":            settings.Image.Hub,
 		"ImageTag":            strings.TrimSuffix(settings.Image.Tag, "-distroless"),
@@ -679,7 +717,7 @@ func templateParams(cfg echo.Config, settings *resource.Settings) (map[string]in
 		"ProxylessGRPC":       cfg.IsProxylessGRPC(),
 		"GRPCMagicPort":       grpcMagicPort,
 		"Locality":            cfg.Locality,
-		"ServiceAccount":      cfg.ServiceAccount,
+		"ServiceAccount":      createdServiceAccount(cfg),
 		"Ports":               cfg.Ports,
 		"WorkloadOnlyPorts":   cfg.WorkloadOnlyPorts,
 		"ContainerPorts":      getContainerPorts(cfg),
@@ -688,8 +726,11 @@ func templateParams(cfg echo.Config, settings *resource.Settings) (map[string]in
 		"TLSSettings":         cfg.TLSSettings,
 		"Cluster":             cfg.Cluster.Name(),
 		"Namespace":           namespace,
-		"ReadinessTCPPort":    cfg.ReadinessTCPPort,
-		"ReadinessGRPCPort":   cfg.ReadinessGRPCPort,
+		"Readiness":           readiness,
+		"Volumes":             cfg.Volumes,
+		"VolumeMounts":        cfg.VolumeMounts,
+		"ReadinessProbePath":  readinessProbePath(cfg),
+		"ReadinessUserAgent":  echoCommon.ReadinessProbeUserAgent,
 		"VM": map[string]interface{}{
 			"Image": vmImage,
 		},
@@ -763,7 +804,7 @@ spec:
 		}
 	}
 
-	if cfg.ServiceAccount {
+	if createdServiceAccount(cfg) != "" {
 		// create service account, the next workload command will use it to generate a token
 		err = createServiceAccount(cfg.Cluster, cfg.Namespace.Name(), serviceAccount(cfg))
 		if err != nil && !kerrors.IsAlreadyExists(err) {
@@ -923,6 +964,66 @@ func createServiceAccount(client kubernetes.Interface, ns string, serviceAccount
 	return err
 }
 
+// readinessProbePath returns the path of the HTTP readiness probe, defaulting to "/".
+func readinessProbePath(cfg echo.Config) string {
+	if cfg.ReadinessProbePath != "" {
+		return cfg.ReadinessProbePath
+	}
+	return "/"
+}
+
+// readinessProbe holds the settings of the readiness probe of the workloads. If neither port is set, an HTTP
+// probe is used.
+type readinessProbe struct {
+	TCPPort             string
+	GRPCPort            string
+	InitialDelaySeconds int
+	PeriodSeconds       int
+}
+
+// getReadinessProbe resolves the readiness probe settings from cfg.ReadinessProbe, falling back to the
+// defaults for any field that is not set.
+func getReadinessProbe(cfg echo.Config) (readinessProbe, error) {
+	probe := readinessProbe{
+		TCPPort:             cfg.ReadinessTCPPort,
+		GRPCPort:            cfg.ReadinessGRPCPort,
+		InitialDelaySeconds: 1,
+		PeriodSeconds:       2,
+	}
+	p := cfg.ReadinessProbe
+	if p == nil {
+		return probe, nil
+	}
+	switch p.Type {
+	case "":
+	case echo.ProbeHTTP:
+		probe.TCPPort, probe.GRPCPort = "", ""
+	case echo.ProbeTCP:
+		probe.GRPCPort = ""
+		if probe.TCPPort == "" {
+			probe.TCPPort = strconv.Itoa(tcpHealthPort)
+		}
+	case echo.ProbeGRPC:
+		probe.TCPPort = ""
+		if probe.GRPCPort == "" {
+			port := cfg.GetPortForProtocol(protocol.GRPC)
+			if port == nil {
+				return probe, fmt.Errorf("gRPC readiness probe for %s requires a GRPC port", cfg.Service)
+			}
+			probe.GRPCPort = strconv.Itoa(port.InstancePort)
+		}
+	default:
+		return probe, fmt.Errorf("unsupported readiness probe type %q", p.Type)
+	}
+	if p.InitialDelay > 0 {
+		probe.InitialDelaySeconds = int(p.InitialDelay / time.Second)
+	}
+	if p.PeriodSeconds > 0 {
+		probe.PeriodSeconds = p.PeriodSeconds
+	}
+	return probe, nil
+}
+
 // getContainerPorts converts the ports to a port list of container ports.
 // Adds ports for health/readiness if necessary.
 func getContainerPorts(cfg echo.Config) echoCommon.PortList {
@@ -18,13 +18,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
 	kubeCore "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/test"
 	echoClient "istio.io/istio/pkg/test/echo"
 	"istio.io/istio/pkg/test/echo/common/scheme"
@@ -32,6 +32,7 @@ import (
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/common"
 	"istio.io/istio/pkg/test/framework/resource"
+	"istio.io/istio/pkg/test/scopes"
 	"istio.io/istio/pkg/test/util/retry"
 	"istio.io/istio/pkg/util/istiomultierror"
 )
@@ -190,13 +191,86 @@ func (c *instance) Restart() error {
 	}, retry.Timeout(c.cfg.ReadinessTimeout), startDelay)
 }
 
-// aggregateResponses forwards an echo request from all workloads belonging to this echo instance and aggregates the results.
-func (c *instance) aggregateResponses(opts echo.CallOptions) (echoClient.Responses, error) {
-	// TODO put this somewhere else, or require users explicitly set the protocol - quite hacky
-	if c.Config().IsProxylessGRPC() && (opts.Scheme == scheme.GRPC || opts.PortName == "grpc" || opts.Port != nil && opts.Port.Protocol == protocol.GRPC) {
+func (c *instance) CallFromWorkload(w echo.Workload, opts echo.CallOptions) (echoClient.Responses, error) {
+	kw, ok := w.(*workload)
+	if !ok {
+		return nil, fmt.Errorf("workload %s is not a kubernetes workload", w.PodName())
+	}
+	if err := c.fillCallDefaults(&opts); err != nil {
+		return nil, err
+	}
+	serviceName := fmt.Sprintf("%s (pod=%s)", c.cfg.Service, w.PodName())
+	return common.ForwardEcho(serviceName, kw.Client, &opts)
+}
+
+func (c *instance) Logs(container string) (map[string]string, error) {
+	workloads, err := c.Workloads()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(workloads))
+	for _, w := range workloads {
+		logs, err := w.(*workload).containerLogs(container)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting %s logs of pod %s: %v", container, w.PodName(), err)
+		}
+		out[w.PodName()] = logs
+	}
+	return out, nil
+}
+
+func (c *instance) LogsOrFail(t test.Failer, container string) map[string]string {
+	t.Helper()
+	out, err := c.Logs(container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// maxDumpedLogLines is the number of trailing lines of each container log written by dumpLogs.
+const maxDumpedLogLines = 100
+
+// dumpLogs writes the last lines of the sidecar and app logs of the workload to the log after a failed call.
+func (c *instance) dumpLogs(w *workload) {
+	for _, container := range []string{proxyContainerName, appContainerName} {
+		logs, err := w.containerLogs(container)
+		if err != nil {
+			scopes.Framework.Warnf("failed getting %s logs of pod %s: %v", container, w.PodName(), err)
+			continue
+		}
+		scopes.Framework.Infof("last %d lines of %s logs of pod %s:\n%s", maxDumpedLogLines, container, w.PodName(),
+			lastLines(logs, maxDumpedLogLines))
+	}
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fillCallDefaults resolves the Port and Scheme up front, so that the scheme is determined by the port's
+// protocol regardless of whether the call specified Port or PortName.
+func (c *instance) fillCallDefaults(opts *echo.CallOptions) error {
+	if err := opts.FillDefaults(); err != nil {
+		return err
+	}
+	if c.Config().IsProxylessGRPC() && opts.Scheme == scheme.GRPC {
 		// for gRPC calls, use XDS resolver
 		opts.Scheme = scheme.XDS
 	}
+	return nil
+}
+
+// aggregateResponses forwards an echo request from all workloads belonging to this echo instance and aggregates the results.
+func (c *instance) aggregateResponses(opts echo.CallOptions) (echoClient.Responses, error) {
+	if err := c.fillCallDefaults(&opts); err != nil {
+		return nil, err
+	}
 
 	resps := make(echoClient.Responses, 0)
 	workloads, err := c.Workloads()
@@ -210,6 +284,7 @@ func (c *instance) aggregateResponses(opts echo.CallOptions) (echoClient.Respons
 
 		out, err := common.ForwardEcho(serviceName, w.(*workload).Client, &opts)
 		if err != nil {
+			c.dumpLogs(w.(*workload))
 			aggErr = multierror.Append(aggErr, err)
 			continue
 		}
@@ -24,6 +24,10 @@ import (
 	"github.com/prometheus/common/expfmt"
 	"google.golang.org/protobuf/proto"
 	kubeCore "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	// Import all XDS config types
 	_ "istio.io/istio/pkg/config/xds"
@@ -39,6 +43,8 @@ const (
 	proxyContainerName = "istio-proxy"
 )
 
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
 var _ echo.Sidecar = &sidecar{}
 
 type sidecar struct {
@@ -153,6 +159,45 @@ func (s *sidecar) StatsOrFail(t test.Failer) map[string]*dto.MetricFamily {
 	return stats
 }
 
+func (s *sidecar) ResourceUsage() (int64, uint64, error) {
+	stats, err := s.proxyStats()
+	if err != nil {
+		return 0, 0, err
+	}
+	allocated := stats["envoy_server_memory_allocated"]
+	if len(allocated.GetMetric()) == 0 {
+		return 0, 0, fmt.Errorf("server.memory_allocated is not reported by pod %s/%s", s.podNamespace, s.podName)
+	}
+	mem := uint64(allocated.GetMetric()[0].GetGauge().GetValue())
+
+	// Envoy does not report its CPU usage, so read it from the metrics API instead.
+	podMetrics, err := s.cluster.Dynamic().Resource(podMetricsGVR).Namespace(s.podNamespace).
+		Get(context.TODO(), s.podName, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed getting metrics of pod %s/%s: %v", s.podNamespace, s.podName, err)
+	}
+	containers, _, err := unstructured.NestedSlice(podMetrics.Object, "containers")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed parsing metrics of pod %s/%s: %v", s.podNamespace, s.podName, err)
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok || container["name"] != proxyContainerName {
+			continue
+		}
+		cpu, _, err := unstructured.NestedString(container, "usage", "cpu")
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed parsing metrics of pod %s/%s: %v", s.podNamespace, s.podName, err)
+		}
+		q, err := resource.ParseQuantity(cpu)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed parsing cpu usage %q: %v", cpu, err)
+		}
+		return q.MilliValue(), mem, nil
+	}
+	return 0, 0, fmt.Errorf("no metrics reported for container %s of pod %s/%s", proxyContainerName, s.podNamespace, s.podName)
+}
+
 func (s *sidecar) proxyStats() (map[string]*dto.MetricFamily, error) {
 	// Exec onto the pod and make a curl request to the admin port, writing
 	command := "pilot-agent request GET /stats/prometheus"
@@ -48,10 +48,22 @@ func newPodSelector(cfg echo.Config) podSelector {
 	}
 }
 
-func serviceAccount(cfg echo.Config) string {
+// createdServiceAccount returns the name of the service account created for the deployment, or an empty string
+// if none is created.
+func createdServiceAccount(cfg echo.Config) string {
+	if cfg.ServiceAccountName != "" {
+		return cfg.ServiceAccountName
+	}
 	if cfg.ServiceAccount {
 		return cfg.Service
 	}
+	return ""
+}
+
+func serviceAccount(cfg echo.Config) string {
+	if sa := createdServiceAccount(cfg); sa != "" {
+		return sa
+	}
 	if cfg.DeployAsVM {
 		return "default"
 	}
@@ -155,7 +155,14 @@ func (w *workload) Sidecar() echo.Sidecar {
 }
 
 func (w *workload) Logs() (string, error) {
-	return w.cluster.PodLogs(context.TODO(), w.pod.Name, w.pod.Namespace, appContainerName, false)
+	return w.containerLogs(appContainerName)
+}
+
+func (w *workload) containerLogs(container string) (string, error) {
+	w.mutex.Lock()
+	pod := w.pod
+	w.mutex.Unlock()
+	return w.cluster.PodLogs(context.TODO(), pod.Name, pod.Namespace, container, false)
 }
 
 func (w *workload) LogsOrFail(t test.Failer) string {
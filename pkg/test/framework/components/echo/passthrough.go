@@ -0,0 +1,77 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/http/headers"
+	"istio.io/istio/pkg/test/echo"
+	"istio.io/istio/pkg/test/echo/check"
+)
+
+// PassThroughCallOptions returns the options for calling the given port of the first workload of to directly
+// by its IP, so that the request is handled by the inbound pass-through filter chain. This is how ports in
+// WorkloadOnlyPorts, which are not part of the Service, are reached. Target is left unset since the port does
+// not match any port of the Service; callers may set Count, Message and other options on the result.
+func PassThroughCallOptions(to Instance, port Port, allowed bool) (CallOptions, error) {
+	workloads, err := to.Workloads()
+	if err != nil {
+		return CallOptions{}, err
+	}
+	if len(workloads) == 0 {
+		return CallOptions{}, fmt.Errorf("no workloads found for %s", to.Config().Service)
+	}
+	address := workloads[0].Address()
+	return CallOptions{
+		Port:    &port,
+		Address: address,
+		HTTP: HTTP{
+			Headers: headers.New().WithHost(net.JoinHostPort(address, strconv.Itoa(port.ServicePort))).Build(),
+		},
+		Check: PassThroughCheck(port, allowed),
+	}, nil
+}
+
+// PassThroughCheck checks a call to a workload port through the pass-through filter chain. If allowed, the
+// call must succeed, with a 200 status for HTTP ports. Otherwise, the call must either fail or be rejected
+// by the authorization policy with a 403.
+func PassThroughCheck(port Port, allowed bool) check.Checker {
+	return func(responses echo.Responses, err error) error {
+		if allowed {
+			if err != nil {
+				return fmt.Errorf("want allow but got error: %v", err)
+			}
+			if responses.Len() < 1 {
+				return fmt.Errorf("received no responses from request to port %d", port.ServicePort)
+			}
+			if okErr := check.OK().Check(responses, err); okErr != nil && port.Protocol == protocol.HTTP {
+				return fmt.Errorf("want status %d but got %s", http.StatusOK, okErr.Error())
+			}
+			return nil
+		}
+		if responses.Len() >= 1 && check.Status(http.StatusForbidden).Check(responses, err) == nil {
+			return nil
+		}
+		if err == nil {
+			return fmt.Errorf("want error but got none: %v", responses.String())
+		}
+		return nil
+	}
+}
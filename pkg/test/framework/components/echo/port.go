@@ -38,6 +38,14 @@ type Port struct {
 	// This need not be the same as the ServicePort where the service is accessed.
 	InstancePort int
 
+	// TargetPort (k8s only) overrides the targetPort of the Service, which otherwise defaults to the
+	// InstancePort. This allows tests to validate Service targetPort remapping.
+	TargetPort int
+
+	// AppProtocol (k8s only) sets the appProtocol of the Service port, which Istio uses for protocol selection
+	// ahead of the port name. It does not affect the container port or how echo serves the port.
+	AppProtocol string
+
 	// TLS determines whether the connection will be plain text or TLS. By default this is false (plain text).
 	TLS bool
 
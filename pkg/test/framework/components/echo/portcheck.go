@@ -0,0 +1,49 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"fmt"
+	"strings"
+
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/echo/check"
+)
+
+// CheckAllPorts sends traffic from the given instance to every port of to that is exposed on its Service,
+// and fails the test listing each port whose call did not succeed. Ports that fail do not stop the others
+// from being called, so a single broken listener is reported alongside the ports that still work.
+func CheckAllPorts(t test.Failer, to Instance, from Instance) {
+	t.Helper()
+	var failed []string
+	for _, port := range to.Config().Ports {
+		if port.ServicePort == 0 {
+			// Not reachable through the Service.
+			continue
+		}
+		_, err := from.Call(CallOptions{
+			Target:   to,
+			PortName: port.Name,
+			Check:    check.OK(),
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s/%d): %v", port.Name, port.Protocol, port.ServicePort, err))
+		}
+	}
+	if len(failed) > 0 {
+		t.Fatalf("calls from %s to %s failed on %d port(s):\n%s",
+			from.Config().Service, to.Config().Service, len(failed), strings.Join(failed, "\n"))
+	}
+}
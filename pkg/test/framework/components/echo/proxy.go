@@ -0,0 +1,358 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/pmezard/go-difflib/difflib"
+	"google.golang.org/protobuf/proto"
+
+	"istio.io/istio/istioctl/pkg/util/configdump"
+	"istio.io/istio/pkg/test/framework/components/istioctl"
+	"istio.io/istio/pkg/test/framework/resource"
+	"istio.io/istio/pkg/test/util/retry"
+	"istio.io/istio/pkg/util/protomarshal"
+)
+
+// EnvoyCluster is an Envoy cluster, along with the addresses of its endpoints as seen by the proxy.
+type EnvoyCluster struct {
+	// Name of the cluster (e.g. outbound|80||server.ns.svc.cluster.local).
+	Name string
+	// Host of the cluster (e.g. server.ns.svc.cluster.local).
+	Host string
+	// Endpoints of the cluster, as host:port.
+	Endpoints []string
+}
+
+// firstSidecar returns the Sidecar of the first workload of the given instance.
+func firstSidecar(i Instance) (Sidecar, error) {
+	_, s, err := firstWorkload(i)
+	return s, err
+}
+
+// firstWorkload returns the first workload of the given instance, along with its Sidecar.
+func firstWorkload(i Instance) (Workload, Sidecar, error) {
+	workloads, err := i.Workloads()
+	if err != nil {
+		return nil, nil, err
+	}
+	s := workloads[0].Sidecar()
+	if s == nil {
+		return nil, nil, fmt.Errorf("no sidecar for echo %s", i.Config().Service)
+	}
+	return workloads[0], s, nil
+}
+
+// ProxyClusters returns the Envoy clusters, and their endpoints, known by the sidecar of the first workload
+// of the given instance, for hosts matching hostPrefix. This can be used to verify that endpoints have
+// propagated to the proxy before making calls.
+func ProxyClusters(i Instance, hostPrefix string) ([]EnvoyCluster, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := s.Clusters()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []EnvoyCluster
+	for _, cs := range clusters.GetClusterStatuses() {
+		host := cs.GetName()
+		// Istio cluster names are of the form direction|port|subset|host.
+		if parts := strings.Split(host, "|"); len(parts) == 4 {
+			host = parts[3]
+		}
+		if !strings.HasPrefix(host, hostPrefix) {
+			continue
+		}
+		c := EnvoyCluster{
+			Name: cs.GetName(),
+			Host: host,
+		}
+		for _, hs := range cs.GetHostStatuses() {
+			addr := hs.GetAddress().GetSocketAddress()
+			if addr == nil {
+				continue
+			}
+			c.Endpoints = append(c.Endpoints, net.JoinHostPort(addr.GetAddress(), strconv.Itoa(int(addr.GetPortValue()))))
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// HasListener returns true if the sidecar of the first workload of the given instance has a listener on the given
+// port, or a filter chain matching the port (e.g. for inbound ports within the virtualInbound listener).
+func HasListener(i Instance, port int) (bool, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return false, err
+	}
+	cfg, err := s.Config()
+	if err != nil {
+		return false, err
+	}
+	dump, err := (&configdump.Wrapper{ConfigDump: cfg}).GetDynamicListenerDump(false)
+	if err != nil {
+		return false, err
+	}
+	for _, dl := range dump.GetDynamicListeners() {
+		l := &listener.Listener{}
+		if err := dl.GetActiveState().GetListener().UnmarshalTo(l); err != nil {
+			return false, err
+		}
+		if int(l.GetAddress().GetSocketAddress().GetPortValue()) == port {
+			return true, nil
+		}
+		for _, fc := range l.GetFilterChains() {
+			if int(fc.GetFilterChainMatch().GetDestinationPort().GetValue()) == port {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// UsesDeltaXDS returns true if the sidecar of the first workload of the given instance is configured to use the
+// delta xDS protocol for its ADS connection, as read from the bootstrap in the proxy's config dump.
+func UsesDeltaXDS(i Instance) (bool, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return false, err
+	}
+	cfg, err := s.Config()
+	if err != nil {
+		return false, err
+	}
+	dump, err := (&configdump.Wrapper{ConfigDump: cfg}).GetBootstrapConfigDump()
+	if err != nil {
+		return false, err
+	}
+	apiType := dump.GetBootstrap().GetDynamicResources().GetAdsConfig().GetApiType()
+	return apiType == core.ApiConfigSource_DELTA_GRPC, nil
+}
+
+// DiffConfig returns a unified diff of the dynamic clusters, listeners and routes of the sidecars of the first
+// workloads of the given instances. Versions and timestamps are stripped, and each instance's own FQDN and
+// workload address are replaced with placeholders, so that functionally equivalent proxies (e.g. delta and
+// SotW xDS) produce an empty diff.
+func DiffConfig(a, b Instance) (string, error) {
+	aConfig, err := normalizedConfig(a)
+	if err != nil {
+		return "", err
+	}
+	bConfig, err := normalizedConfig(b)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		FromFile: a.Config().Service,
+		A:        difflib.SplitLines(aConfig),
+		ToFile:   b.Config().Service,
+		B:        difflib.SplitLines(bConfig),
+		Context:  3,
+	})
+}
+
+// normalizedConfig returns the dynamic config of the first workload's sidecar as JSON, with instance-specific
+// values replaced so that it can be compared against another instance.
+func normalizedConfig(i Instance) (string, error) {
+	workload, s, err := firstWorkload(i)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := s.Config()
+	if err != nil {
+		return "", err
+	}
+	w := &configdump.Wrapper{ConfigDump: cfg}
+	clusters, err := w.GetDynamicClusterDump(true)
+	if err != nil {
+		return "", err
+	}
+	listeners, err := w.GetDynamicListenerDump(true)
+	if err != nil {
+		return "", err
+	}
+	routes, err := w.GetDynamicRouteDump(true)
+	if err != nil {
+		return "", err
+	}
+
+	sb := strings.Builder{}
+	for _, m := range []proto.Message{clusters, listeners, routes} {
+		js, err := protomarshal.ToJSONWithIndent(m, "  ")
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(js)
+		sb.WriteString("\n")
+	}
+	out := strings.ReplaceAll(sb.String(), i.Config().ClusterLocalFQDN(), "{{FQDN}}")
+	return strings.ReplaceAll(out, workload.Address(), "{{WORKLOAD_ADDRESS}}"), nil
+}
+
+// ProxyResourceUsage returns the CPU usage, in millicores, and the memory allocated by Envoy, in bytes, of the
+// sidecar of the first workload of the given instance. The metrics API averages CPU usage over its scrape
+// window, so readings taken while generating load may lag behind it.
+func ProxyResourceUsage(i Instance) (int64, uint64, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return 0, 0, err
+	}
+	return s.ResourceUsage()
+}
+
+// ConfigSize returns the size, in bytes, of the serialized config dump of the sidecar of the first workload
+// of the given instance.
+func ConfigSize(i Instance) (int, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return 0, err
+	}
+	cfg, err := s.Config()
+	if err != nil {
+		return 0, err
+	}
+	return proto.Size(cfg), nil
+}
+
+// ClusterCount returns the number of dynamic clusters known by the sidecar of the first workload of the given
+// instance.
+func ClusterCount(i Instance) (int, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return 0, err
+	}
+	cfg, err := s.Config()
+	if err != nil {
+		return 0, err
+	}
+	dump, err := (&configdump.Wrapper{ConfigDump: cfg}).GetDynamicClusterDump(false)
+	if err != nil {
+		return 0, err
+	}
+	return len(dump.GetDynamicActiveClusters()), nil
+}
+
+// VerifyConfigReduced calls apply, which is expected to narrow the config of the given instance (e.g. by applying
+// a Sidecar egress scope), and waits until the sidecar of its first workload knows fewer clusters than it did
+// beforehand. An error is returned if the cluster count never drops.
+func VerifyConfigReduced(i Instance, apply func() error, opts ...retry.Option) error {
+	before, err := ClusterCount(i)
+	if err != nil {
+		return err
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	return retry.UntilSuccess(func() error {
+		after, err := ClusterCount(i)
+		if err != nil {
+			return err
+		}
+		if after >= before {
+			return fmt.Errorf("expected fewer than %d clusters for %s, got %d", before, i.Config().Service, after)
+		}
+		return nil
+	}, opts...)
+}
+
+// ProxyConfig returns the output of "istioctl proxy-config <kind>" (e.g. cluster, listener, route or endpoint)
+// for the first workload of the given instance.
+func ProxyConfig(ctx resource.Context, i Instance, kind string) ([]byte, error) {
+	workloads, err := i.Workloads()
+	if err != nil {
+		return nil, err
+	}
+	ik, err := istioctl.New(ctx, istioctl.Config{Cluster: i.Config().Cluster})
+	if err != nil {
+		return nil, err
+	}
+	podID := workloads[0].PodName() + "." + i.Config().Namespace.Name()
+	stdout, stderr, err := ik.Invoke([]string{"proxy-config", kind, podID})
+	if err != nil {
+		return nil, fmt.Errorf("istioctl proxy-config %s %s failed: %v\nerr: %v", kind, podID, err, stderr)
+	}
+	return []byte(stdout), nil
+}
+
+// InjectedRevision returns the Istio revision that injected the sidecar of the first workload of the given
+// instance, as reported in the proxy's node metadata.
+func InjectedRevision(i Instance) (string, error) {
+	return nodeMetadata(i, "ISTIO_REVISION")
+}
+
+// ProxyVersion returns the Istio version of the sidecar of the first workload of the given instance, as
+// reported in the proxy's node metadata.
+func ProxyVersion(i Instance) (string, error) {
+	return nodeMetadata(i, "ISTIO_VERSION")
+}
+
+// ProxyMetadata returns the proxyMetadata in effect for the sidecar of the first workload of the given instance,
+// as reported in the proxy config of its node metadata. This includes the metadata set through the
+// proxy.istio.io/config annotation, such as ISTIO_DELTA_XDS or OUTPUT_CERTS, once it has been applied.
+func ProxyMetadata(i Instance) (map[string]string, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := s.Config()
+	if err != nil {
+		return nil, err
+	}
+	dump, err := (&configdump.Wrapper{ConfigDump: cfg}).GetBootstrapConfigDump()
+	if err != nil {
+		return nil, err
+	}
+	proxyConfig := dump.GetBootstrap().GetNode().GetMetadata().GetFields()["PROXY_CONFIG"].GetStructValue()
+	if proxyConfig == nil {
+		return nil, fmt.Errorf("no PROXY_CONFIG found in proxy metadata for %s", i.Config().Service)
+	}
+	out := map[string]string{}
+	for k, v := range proxyConfig.GetFields()["proxyMetadata"].GetStructValue().GetFields() {
+		out[k] = v.GetStringValue()
+	}
+	return out, nil
+}
+
+// nodeMetadata returns the value of the given key in the node metadata of the bootstrap of the sidecar of the
+// first workload of the given instance.
+func nodeMetadata(i Instance, key string) (string, error) {
+	s, err := firstSidecar(i)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := s.Config()
+	if err != nil {
+		return "", err
+	}
+	dump, err := (&configdump.Wrapper{ConfigDump: cfg}).GetBootstrapConfigDump()
+	if err != nil {
+		return "", err
+	}
+	v := dump.GetBootstrap().GetNode().GetMetadata().GetFields()[key].GetStringValue()
+	if v == "" {
+		return "", fmt.Errorf("no %s found in proxy metadata for %s", key, i.Config().Service)
+	}
+	return v, nil
+}
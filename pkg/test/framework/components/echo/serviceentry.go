@@ -0,0 +1,104 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"istio.io/istio/pkg/test/util/tmpl"
+)
+
+const serviceEntryTemplate = `
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: {{ .Name }}
+spec:
+  hosts:
+{{- range $h := .Hosts }}
+  - {{ $h }}
+{{- end }}
+  location: {{ .Location }}
+  resolution: {{ .Resolution }}
+{{- if .Address }}
+  endpoints:
+  - address: {{ .Address }}
+{{- end }}
+  ports:
+{{- range $p := .Ports }}
+  - name: {{ $p.Name }}
+    number: {{ $p.ServicePort }}
+    protocol: "{{ $p.Protocol }}"
+{{- if $p.TargetPort }}
+    targetPort: {{ $p.TargetPort }}
+{{- end }}
+{{- end }}
+`
+
+// ServiceEntryOptions for generating a ServiceEntry with ServiceEntryFor.
+type ServiceEntryOptions struct {
+	// Name of the ServiceEntry. Defaults to the service name of the instance.
+	Name string
+
+	// Hosts of the ServiceEntry. Defaults to the host header of the instance.
+	Hosts []string
+
+	// Location of the ServiceEntry. Defaults to MESH_EXTERNAL.
+	Location string
+
+	// Resolution of the ServiceEntry. Defaults to DNS.
+	Resolution string
+
+	// Ports of the ServiceEntry. Defaults to the ports of the instance. If TargetPort is set,
+	// it will be used as the targetPort of the ServiceEntry port.
+	Ports []Port
+}
+
+// ServiceEntryFor generates the YAML for a ServiceEntry that matches the given echo instance. With DNS
+// resolution, the endpoint is the cluster-local FQDN of the instance. With STATIC resolution, the endpoint
+// is the address of the instance. With NONE resolution, no endpoints are generated.
+func ServiceEntryFor(i Instance, opts ServiceEntryOptions) (string, error) {
+	cfg := i.Config()
+	if opts.Name == "" {
+		opts.Name = cfg.Service
+	}
+	if len(opts.Hosts) == 0 {
+		opts.Hosts = []string{cfg.HostHeader()}
+	}
+	if opts.Location == "" {
+		opts.Location = "MESH_EXTERNAL"
+	}
+	if opts.Resolution == "" {
+		opts.Resolution = "DNS"
+	}
+	if opts.Ports == nil {
+		opts.Ports = cfg.Ports
+	}
+
+	var address string
+	switch opts.Resolution {
+	case "DNS":
+		address = cfg.ClusterLocalFQDN()
+	case "STATIC":
+		address = i.Address()
+	}
+
+	return tmpl.EvaluateStrict(serviceEntryTemplate, map[string]interface{}{
+		"Name":       opts.Name,
+		"Hosts":      opts.Hosts,
+		"Location":   opts.Location,
+		"Resolution": opts.Resolution,
+		"Address":    address,
+		"Ports":      opts.Ports,
+	})
+}
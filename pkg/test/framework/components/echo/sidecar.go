@@ -52,4 +52,8 @@ type Sidecar interface {
 	LogsOrFail(t test.Failer) string
 	Stats() (map[string]*dto.MetricFamily, error)
 	StatsOrFail(t test.Failer) map[string]*dto.MetricFamily
+
+	// ResourceUsage returns the CPU usage of the sidecar container, in millicores, as reported by the metrics
+	// API, and the memory allocated by Envoy, in bytes, as reported by its server.memory_allocated stat.
+	ResourceUsage() (int64, uint64, error)
 }
@@ -136,6 +136,16 @@ func (i *instance) Call(opts echo.CallOptions) (echoClient.Responses, error) {
 	return common.ForwardEcho(i.Config().Service, i.defaultClient, &opts)
 }
 
+func (i *instance) CallFromWorkload(w echo.Workload, opts echo.CallOptions) (echoClient.Responses, error) {
+	vw, ok := w.(*workload)
+	if !ok {
+		return nil, fmt.Errorf("workload %s is not a static VM workload", w.PodName())
+	}
+	return common.ForwardEcho(i.Config().Service, func() (*echoClient.Client, error) {
+		return vw.Client, nil
+	}, &opts)
+}
+
 func (i *instance) CallOrFail(t test.Failer, opts echo.CallOptions) echoClient.Responses {
 	t.Helper()
 	res, err := i.Call(opts)
@@ -145,6 +155,19 @@ func (i *instance) CallOrFail(t test.Failer, opts echo.CallOptions) echoClient.R
 	return res
 }
 
+func (i *instance) Logs(container string) (map[string]string, error) {
+	return nil, fmt.Errorf("cannot get %s logs of static VM %s", container, i.Config().Service)
+}
+
+func (i *instance) LogsOrFail(t test.Failer, container string) map[string]string {
+	t.Helper()
+	out, err := i.Logs(container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
 func (i *instance) Restart() error {
 	panic("cannot trigger restart of a static VM")
 }
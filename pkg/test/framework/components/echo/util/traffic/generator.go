@@ -15,6 +15,7 @@
 package traffic
 
 import (
+	"fmt"
 	"time"
 
 	"istio.io/istio/pkg/test"
@@ -53,6 +54,11 @@ type Generator interface {
 	// Stop sending traffic and wait for any in-flight requests to complete.
 	// Returns the Result
 	Stop() Result
+
+	// NoDroppedDuring sends traffic while restartFn runs, and returns an error if restartFn fails or if
+	// any request failed its check, e.g. with a connection reset or a 503. This verifies that connections
+	// are drained gracefully during a restart.
+	NoDroppedDuring(restartFn func() error) error
 }
 
 // NewGenerator returns a new Generator with the given configuration.
@@ -114,6 +120,20 @@ func (g *generator) Stop() Result {
 	return Result{}
 }
 
+func (g *generator) NoDroppedDuring(restartFn func() error) error {
+	g.Start()
+	err := restartFn()
+	result := g.Stop()
+	if err != nil {
+		return err
+	}
+	if result.SuccessfulRequests != result.TotalRequests {
+		return fmt.Errorf("%d/%d requests were dropped during the restart: %v",
+			result.TotalRequests-result.SuccessfulRequests, result.TotalRequests, result.Error)
+	}
+	return nil
+}
+
 func fillInDefaults(cfg *Config) {
 	if cfg.Interval == 0 {
 		cfg.Interval = defaultInterval
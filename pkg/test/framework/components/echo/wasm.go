@@ -0,0 +1,106 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"fmt"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"istio.io/istio/pkg/test/echo"
+	"istio.io/istio/pkg/test/echo/check"
+)
+
+// wasmFilterLabel is the tag that Envoy extracts from the stats of each Wasm filter, holding the filter name.
+const wasmFilterLabel = "wasm_filter"
+
+// WasmProcessed returns a checker that verifies that the Wasm filter with the given name processed the calls
+// to target, by checking that the Wasm stats its sidecars report for the filter increased. The baseline is read
+// when the checker is created, so it must be created right before the call.
+func WasmProcessed(target Instance, filterName string) check.Checker {
+	// The stats may not be reported yet if the filter has not loaded.
+	before, _ := wasmFilterStats(target, filterName)
+	return func(_ echo.Responses, err error) error {
+		if err != nil {
+			return err
+		}
+		after, err := wasmFilterStats(target, filterName)
+		if err != nil {
+			return err
+		}
+		if after <= before {
+			return fmt.Errorf("wasm stats of filter %s did not increase from %v, the filter did not process the requests",
+				filterName, before)
+		}
+		return nil
+	}
+}
+
+// wasmFilterStats returns the sum of the Wasm stats reported for the given filter by the sidecars of target,
+// failing if none of them runs a Wasm VM.
+func wasmFilterStats(target Instance, filterName string) (float64, error) {
+	workloads, err := target.Workloads()
+	if err != nil {
+		return 0, err
+	}
+	var sum float64
+	for _, w := range workloads {
+		s := w.Sidecar()
+		if s == nil {
+			return 0, fmt.Errorf("no sidecar for echo %s", target.Config().Service)
+		}
+		stats, err := s.Stats()
+		if err != nil {
+			return 0, err
+		}
+		if !wasmVMActive(stats) {
+			return 0, fmt.Errorf("no Wasm VM is active on %s", w.PodName())
+		}
+		sum += sumWasmFilterStats(stats, filterName)
+	}
+	return sum, nil
+}
+
+// wasmVMActive returns whether any Wasm runtime reports an active VM.
+func wasmVMActive(stats map[string]*dto.MetricFamily) bool {
+	for name, mf := range stats {
+		if !strings.HasPrefix(name, "envoy_wasm_envoy_wasm_runtime_") || !strings.HasSuffix(name, "_active") {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if m.GetGauge().GetValue() > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sumWasmFilterStats returns the sum of the counters tagged with the given Wasm filter name.
+func sumWasmFilterStats(stats map[string]*dto.MetricFamily, filterName string) float64 {
+	var sum float64
+	for _, mf := range stats {
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == wasmFilterLabel && l.GetValue() == filterName {
+					sum += m.GetCounter().GetValue()
+					break
+				}
+			}
+		}
+	}
+	return sum
+}
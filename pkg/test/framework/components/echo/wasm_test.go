@@ -0,0 +1,53 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+func TestWasmFilterStats(t *testing.T) {
+	stats, err := (&expfmt.TextParser{}).TextToMetricFamilies(strings.NewReader(`
+# TYPE envoy_wasm_envoy_wasm_runtime_v8_active gauge
+envoy_wasm_envoy_wasm_runtime_v8_active{} 2
+# TYPE envoy_wasm_envoy_wasm_runtime_null_active gauge
+envoy_wasm_envoy_wasm_runtime_null_active{} 0
+# TYPE envoy_wasmcustom_requests counter
+envoy_wasmcustom_requests{wasm_filter="my-filter"} 3
+envoy_wasmcustom_requests{wasm_filter="other-filter"} 5
+# TYPE envoy_wasmcustom_errors counter
+envoy_wasmcustom_errors{wasm_filter="my-filter"} 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wasmVMActive(stats) {
+		t.Fatal("expected an active Wasm VM")
+	}
+	if got := sumWasmFilterStats(stats, "my-filter"); got != 4 {
+		t.Fatalf("expected 4 for my-filter, got %v", got)
+	}
+	if got := sumWasmFilterStats(stats, "missing-filter"); got != 0 {
+		t.Fatalf("expected 0 for missing-filter, got %v", got)
+	}
+
+	delete(stats, "envoy_wasm_envoy_wasm_runtime_v8_active")
+	if wasmVMActive(stats) {
+		t.Fatal("expected no active Wasm VM")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"istio.io/istio/istioctl/cmd"
+	"istio.io/istio/pkg/test/framework/components/istioctl"
+)
+
+// AnalysisMessage is a single message reported by "istioctl analyze".
+type AnalysisMessage struct {
+	Code             string `json:"code"`
+	Level            string `json:"level"`
+	Origin           string `json:"origin"`
+	Reference        string `json:"reference"`
+	Message          string `json:"message"`
+	DocumentationURL string `json:"documentationUrl"`
+}
+
+func (i *operatorComponent) Analyze(namespace string) ([]AnalysisMessage, error) {
+	ik, err := istioctl.New(i.ctx, istioctl.Config{})
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"analyze", "--use-kube=true", "-ojson"}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	} else {
+		args = append(args, "--all-namespaces")
+	}
+	// Suppress cluster-wide checks that are not caused by the config under test.
+	args = append(args, "--suppress=IST0139=*", "--suppress=IST0002=CustomResourceDefinition *")
+
+	stdout, stderr, err := ik.Invoke(args)
+	// analyze returns an error if it found any issues, which are reported as messages instead.
+	if err != nil && !errors.As(err, &cmd.AnalyzerFoundIssuesError{}) {
+		return nil, fmt.Errorf("istioctl analyze failed: %v\nerr: %v", err, stderr)
+	}
+	var msgs []AnalysisMessage
+	if err := json.Unmarshal([]byte(stdout), &msgs); err != nil {
+		return nil, fmt.Errorf("failed parsing analysis output %q: %v", stdout, err)
+	}
+	return msgs, nil
+}
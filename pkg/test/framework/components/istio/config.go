@@ -311,6 +311,9 @@ func (c *Config) String() string {
 }
 
 // ClaimSystemNamespace retrieves the namespace for the Istio system components from the environment.
+//
+// Deprecated: tests should use Instance.SystemNamespace. This is only needed by setup functions that run
+// before Istio has been deployed.
 func ClaimSystemNamespace(ctx resource.Context) (namespace.Instance, error) {
 	istioCfg, err := DefaultConfig(ctx)
 	if err != nil {
@@ -322,13 +325,3 @@ func ClaimSystemNamespace(ctx resource.Context) (namespace.Instance, error) {
 	}
 	return namespace.Claim(ctx, nsCfg)
 }
-
-// ClaimSystemNamespaceOrFail calls ClaimSystemNamespace, failing the test if an error occurs.
-func ClaimSystemNamespaceOrFail(t test.Failer, ctx resource.Context) namespace.Instance {
-	t.Helper()
-	i, err := ClaimSystemNamespace(ctx)
-	if err != nil {
-		t.Fatal(err)
-	}
-	return i
-}
@@ -0,0 +1,81 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istio
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"istio.io/istio/pkg/test/env"
+	"istio.io/istio/pkg/test/helm"
+	"istio.io/istio/pkg/test/scopes"
+)
+
+const helmInstallTimeout = 2 * time.Minute
+
+// helmDiscoveryChart returns the path of the istio-discovery chart for the given version. Released versions are
+// read from the packaged charts under tests/integration/helm/testdata, while an empty version uses the charts
+// of the current source tree.
+func helmDiscoveryChart(version string) string {
+	if version == "" {
+		return filepath.Join(env.IstioSrc, "manifests/charts/istio-control/istio-discovery")
+	}
+	return filepath.Join(env.IstioSrc, "tests/integration/helm/testdata", version,
+		"istio-control/istio-discovery.tar.gz")
+}
+
+func (i *operatorComponent) InstallRevisionFromHelm(version string, values map[string]string) (string, error) {
+	revision := strings.ReplaceAll(version, ".", "-")
+	if revision == "" {
+		revision = "helm"
+	}
+	releaseName := "istiod-" + revision
+
+	// Sort the overrides so the generated command is stable.
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := []string{
+		"--set revision=" + revision,
+		"--set global.istioNamespace=" + i.settings.SystemNamespace,
+	}
+	for _, k := range keys {
+		args = append(args, fmt.Sprintf("--set %s=%s", k, values[k]))
+	}
+
+	chart := helmDiscoveryChart(version)
+	for _, c := range i.ctx.Clusters().Primaries().Kube() {
+		kubeConfigFile, err := kubeConfigFileForCluster(c)
+		if err != nil {
+			return "", err
+		}
+		h := helm.New(kubeConfigFile)
+		scopes.Framework.Infof("installing revision %s from helm chart %s in cluster %s", revision, chart, c.Name())
+		if err := h.InstallChartWithValues(releaseName, chart, i.settings.SystemNamespace, args, helmInstallTimeout); err != nil {
+			return "", fmt.Errorf("failed installing revision %s in cluster %s: %v", revision, c.Name(), err)
+		}
+		i.ctx.ConditionalCleanup(func() {
+			if err := h.DeleteChart(releaseName, i.settings.SystemNamespace); err != nil {
+				scopes.Framework.Errorf("failed deleting helm release %s: %v", releaseName, err)
+			}
+		})
+	}
+	return revision, nil
+}
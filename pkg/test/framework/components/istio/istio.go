@@ -20,8 +20,10 @@ import (
 
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/framework/components/cluster"
+	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/environment/kube"
 	"istio.io/istio/pkg/test/framework/components/istio/ingress"
+	"istio.io/istio/pkg/test/framework/components/namespace"
 	"istio.io/istio/pkg/test/framework/resource"
 	"istio.io/istio/pkg/test/scopes"
 )
@@ -44,6 +46,27 @@ type Instance interface {
 	// outside its cluster.
 	RemoteDiscoveryAddressFor(cluster cluster.Cluster) (net.TCPAddr, error)
 	Settings() Config
+	// SystemNamespace claims the namespace of the Istio system components, failing the test if an error
+	// occurs. Injection is never enabled on the claimed namespace.
+	SystemNamespace(t test.Failer) namespace.Instance
+	// Analyze runs "istioctl analyze" against the live cluster for the given namespace, or all namespaces
+	// if empty, and returns the reported messages.
+	Analyze(namespace string) ([]AnalysisMessage, error)
+	// ProxyStatus runs "istioctl proxy-status" against each primary cluster and returns the status of all
+	// connected proxies.
+	ProxyStatus() ([]ProxyStatus, error)
+	// WaitAllProxiesSynced waits until the sidecars of all workloads of the given instances are reported as
+	// synced by "istioctl proxy-status".
+	WaitAllProxiesSynced(instances echo.Instances) error
+	// InstallRevisionFromHelm installs a revisioned control plane running the given version from its Helm charts
+	// in each primary cluster, with the given values passed as --set overrides, and returns the revision name.
+	// An empty version installs the charts from the current source tree.
+	InstallRevisionFromHelm(version string, values map[string]string) (string, error)
+	// UpgradeTo installs a revision running the given version, moves the namespaces of the instances taking part
+	// in checkContinuity to it and restarts their workloads. The traffic of checkContinuity is sent in the
+	// background during the upgrade, and an error is returned if the fraction of successful calls is below
+	// successThreshold. The injection labels of the namespaces are restored when ctx completes.
+	UpgradeTo(ctx resource.Context, version string, checkContinuity echo.Checker, successThreshold float64) error
 }
 
 // SetupConfigFn is a setup function that specifies the overrides of the configuration to deploy Istio.
@@ -41,6 +41,7 @@ import (
 	"istio.io/istio/operator/cmd/mesh"
 	pkgAPI "istio.io/istio/operator/pkg/apis/istio/v1alpha1"
 	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/cert/ca"
 	testenv "istio.io/istio/pkg/test/env"
 	"istio.io/istio/pkg/test/framework/components/cluster"
@@ -48,6 +49,7 @@ import (
 	"istio.io/istio/pkg/test/framework/components/environment/kube"
 	"istio.io/istio/pkg/test/framework/components/istio/ingress"
 	"istio.io/istio/pkg/test/framework/components/istioctl"
+	"istio.io/istio/pkg/test/framework/components/namespace"
 	"istio.io/istio/pkg/test/framework/resource"
 	kube2 "istio.io/istio/pkg/test/kube"
 	"istio.io/istio/pkg/test/scopes"
@@ -100,6 +102,18 @@ func (i *operatorComponent) Settings() Config {
 	return i.settings
 }
 
+func (i *operatorComponent) SystemNamespace(t test.Failer) namespace.Instance {
+	t.Helper()
+	ns, err := namespace.Claim(i.ctx, namespace.Config{
+		Prefix: i.settings.SystemNamespace,
+		Inject: false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ns
+}
+
 func removeCRDsSlice(raw []string) string {
 	res := make([]string, 0)
 	for _, r := range raw {
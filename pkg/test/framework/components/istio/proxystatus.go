@@ -0,0 +1,119 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istio
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"istio.io/istio/pkg/test/framework/components/echo"
+	"istio.io/istio/pkg/test/framework/components/istioctl"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+// ProxyStatus is the sync status of a single proxy, as reported by "istioctl proxy-status".
+type ProxyStatus struct {
+	// Name of the proxy, in the form <pod>.<namespace>.
+	Name    string
+	Cluster string
+	CDS     string
+	LDS     string
+	EDS     string
+	RDS     string
+	Istiod  string
+	Version string
+}
+
+// Synced returns true if no xDS type is stale for the proxy. Types that were never sent are considered synced,
+// since not every proxy needs every type.
+func (s ProxyStatus) Synced() bool {
+	for _, st := range []string{s.CDS, s.LDS, s.EDS, s.RDS} {
+		if strings.HasPrefix(st, "STALE") {
+			return false
+		}
+	}
+	return true
+}
+
+// proxyStatusColumns splits a row of the proxy-status table. Columns are padded with multiple spaces, while
+// values such as "NOT SENT" only contain single spaces.
+var proxyStatusColumns = regexp.MustCompile(`\s{2,}`)
+
+func (i *operatorComponent) ProxyStatus() ([]ProxyStatus, error) {
+	var out []ProxyStatus
+	for _, c := range i.ctx.Clusters().Primaries() {
+		ik, err := istioctl.New(i.ctx, istioctl.Config{Cluster: c})
+		if err != nil {
+			return nil, err
+		}
+		stdout, stderr, err := ik.Invoke([]string{"proxy-status"})
+		if err != nil {
+			return nil, fmt.Errorf("istioctl proxy-status failed in cluster %s: %v\nerr: %v", c.Name(), err, stderr)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		// Skip the header.
+		for _, line := range lines[1:] {
+			fields := proxyStatusColumns.Split(strings.TrimSpace(line), -1)
+			if len(fields) != 8 {
+				return nil, fmt.Errorf("unexpected proxy-status row %q", line)
+			}
+			out = append(out, ProxyStatus{
+				Name:    fields[0],
+				Cluster: fields[1],
+				CDS:     fields[2],
+				LDS:     fields[3],
+				EDS:     fields[4],
+				RDS:     fields[5],
+				Istiod:  fields[6],
+				Version: fields[7],
+			})
+		}
+	}
+	return out, nil
+}
+
+func (i *operatorComponent) WaitAllProxiesSynced(instances echo.Instances) error {
+	return retry.UntilSuccess(func() error {
+		statuses, err := i.ProxyStatus()
+		if err != nil {
+			return err
+		}
+		byName := map[string]ProxyStatus{}
+		for _, s := range statuses {
+			byName[s.Name] = s
+		}
+		for _, inst := range instances {
+			workloads, err := inst.Workloads()
+			if err != nil {
+				return err
+			}
+			for _, w := range workloads {
+				if w.Sidecar() == nil {
+					continue
+				}
+				name := w.PodName() + "." + inst.Config().Namespace.Name()
+				s, ok := byName[name]
+				if !ok {
+					return fmt.Errorf("proxy %s not found in proxy-status", name)
+				}
+				if !s.Synced() {
+					return fmt.Errorf("proxy %s not synced: %+v", name, s)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istio
+
+import (
+	"fmt"
+
+	"istio.io/api/label"
+
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/framework/components/echo"
+	"istio.io/istio/pkg/test/framework/components/echo/util/traffic"
+	"istio.io/istio/pkg/test/framework/components/namespace"
+	"istio.io/istio/pkg/test/framework/resource"
+	"istio.io/istio/pkg/test/scopes"
+)
+
+func (i *operatorComponent) UpgradeTo(ctx resource.Context, version string, checkContinuity echo.Checker,
+	successThreshold float64) error {
+	instances := checkContinuity.Instances()
+	if checkContinuity.Options.Target == nil {
+		return fmt.Errorf("continuity check must have a target instance")
+	}
+	return test.Wrap(func(t test.Failer) {
+		g := traffic.NewGenerator(t, traffic.Config{
+			Source:  checkContinuity.From,
+			Options: checkContinuity.Options,
+		}).Start()
+		stopped := false
+		defer func() {
+			// Don't leave the traffic running if the upgrade fails partway.
+			if !stopped {
+				g.Stop()
+			}
+		}()
+
+		revision, err := i.InstallRevisionFromHelm(version, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Point each namespace at the new revision, so the restarted workloads are injected by it.
+		relabeled := map[string]bool{}
+		for _, inst := range instances {
+			ns := inst.Config().Namespace
+			if relabeled[ns.Name()] {
+				continue
+			}
+			relabeled[ns.Name()] = true
+			original, err := ns.Labels()
+			if err != nil {
+				t.Fatalf("failed reading labels of namespace %s: %v", ns.Name(), err)
+			}
+			ctx.Cleanup(func() {
+				restoreInjectionLabels(ns, original)
+			})
+			if err := ns.RemoveLabel("istio-injection"); err != nil {
+				t.Fatalf("failed removing injection label from namespace %s: %v", ns.Name(), err)
+			}
+			if err := ns.SetLabel(label.IoIstioRev.Name, revision); err != nil {
+				t.Fatalf("failed setting revision label on namespace %s: %v", ns.Name(), err)
+			}
+		}
+		for _, inst := range instances {
+			scopes.Framework.Infof("rolling out echo workloads for service %q to revision %s", inst.Config().Service, revision)
+			if err := inst.Restart(); err != nil {
+				t.Fatalf("failed rolling out %s to revision %s: %v", inst.Config().Service, revision, err)
+			}
+		}
+
+		stopped = true
+		g.Stop().CheckSuccessRate(t, successThreshold)
+	})
+}
+
+// restoreInjectionLabels sets the injection labels of the namespace back to their values in original, removing
+// those that were not set.
+func restoreInjectionLabels(ns namespace.Instance, original map[string]string) {
+	for _, key := range []string{"istio-injection", label.IoIstioRev.Name} {
+		var err error
+		if value, ok := original[key]; ok {
+			err = ns.SetLabel(key, value)
+		} else {
+			err = ns.RemoveLabel(key)
+		}
+		if err != nil {
+			scopes.Framework.Warnf("failed restoring label %s on namespace %s: %v", key, ns.Name(), err)
+		}
+	}
+}
@@ -16,6 +16,7 @@ package namespace
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
@@ -110,6 +111,10 @@ func (n *kubeNamespace) SetLabel(key, value string) error {
 	return n.setNamespaceLabel(key, value)
 }
 
+func (n *kubeNamespace) SetLabels(labels map[string]string) error {
+	return n.setNamespaceLabels(labels)
+}
+
 func (n *kubeNamespace) RemoveLabel(key string) error {
 	return n.removeNamespaceLabel(key)
 }
@@ -164,6 +169,25 @@ func (n *kubeNamespace) setNamespaceLabel(key, value string) error {
 	return nil
 }
 
+// setNamespaceLabels merges the given labels into the labels of the namespace
+func (n *kubeNamespace) setNamespaceLabels(labels map[string]string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, cluster := range n.ctx.Clusters().Kube() {
+		if _, err := cluster.CoreV1().Namespaces().Patch(context.TODO(), n.name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // removeNamespaceLabel removes namespace label with the given key
 func (n *kubeNamespace) removeNamespaceLabel(key string) error {
 	// need to convert '/' to '~1' as per the JSON patch spec http://jsonpatch.com/#operations
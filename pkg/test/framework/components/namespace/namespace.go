@@ -35,6 +35,9 @@ type Config struct {
 type Instance interface {
 	Name() string
 	SetLabel(key, value string) error
+	// SetLabels adds or updates all of the given labels on the live namespace in a single patch, leaving
+	// other labels untouched.
+	SetLabels(labels map[string]string) error
 	RemoveLabel(key string) error
 	Prefix() string
 	Labels() (map[string]string, error)
@@ -38,6 +38,10 @@ func (s Static) SetLabel(key, value string) error {
 	panic("implement me")
 }
 
+func (s Static) SetLabels(labels map[string]string) error {
+	panic("implement me")
+}
+
 func (s Static) RemoveLabel(key string) error {
 	panic("implement me")
 }
@@ -18,9 +18,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +38,7 @@ import (
 	"istio.io/istio/pkg/test/framework/resource"
 	testKube "istio.io/istio/pkg/test/kube"
 	"istio.io/istio/pkg/test/scopes"
+	"istio.io/istio/pkg/test/util/retry"
 )
 
 const (
@@ -54,37 +57,180 @@ type kubeComponent struct {
 	api       map[string]prometheusApiV1.API
 	forwarder map[string]istioKube.PortForwarder
 	clusters  cluster.Clusters
+
+	scrapeInterval time.Duration
+}
+
+const defaultScrapeInterval = 5 * time.Second
+
+// inMeshReplacements turn the sample Prometheus deployment into one that is injected with a sidecar, which only
+// writes its certificates to a volume shared with Prometheus and intercepts no traffic. The SecureScrapeJob then
+// scrapes pods annotated with SecurePortAnnotation over https with those certificates, so that their sidecars
+// terminate the mTLS connection.
+var inMeshReplacements = []struct{ old, new string }{
+	{
+		old: `        sidecar.istio.io/inject: "false"
+`,
+		new: `        sidecar.istio.io/inject: "true"
+      annotations:
+        traffic.sidecar.istio.io/includeInboundPorts: ""
+        traffic.sidecar.istio.io/includeOutboundIPRanges: ""
+        proxy.istio.io/config: |
+          proxyMetadata:
+            OUTPUT_CERTS: /etc/istio-output-certs
+        sidecar.istio.io/userVolumeMount: '[{"name": "istio-certs", "mountPath": "/etc/istio-output-certs"}]'
+`,
+	},
+	{
+		old: `            - name: storage-volume
+              mountPath: /data
+              subPath: ""
+`,
+		new: `            - name: storage-volume
+              mountPath: /data
+              subPath: ""
+            - name: istio-certs
+              mountPath: /etc/prom-certs/
+`,
+	},
+	{
+		old: `        - name: storage-volume
+          emptyDir:
+            {}
+`,
+		new: `        - name: storage-volume
+          emptyDir:
+            {}
+        - name: istio-certs
+          emptyDir:
+            medium: Memory
+`,
+	},
+	{
+		old: `    - job_name: kubernetes-pods
+`,
+		// Workload certificates only carry a SPIFFE URI SAN, which Prometheus cannot verify, so the server
+		// certificate is not verified. The sidecar of the scraped pod still verifies the client certificate.
+		new: `    - job_name: ` + SecureScrapeJob + `
+      scheme: https
+      tls_config:
+        ca_file: /etc/prom-certs/root-cert.pem
+        cert_file: /etc/prom-certs/cert-chain.pem
+        key_file: /etc/prom-certs/key.pem
+        insecure_skip_verify: true
+      kubernetes_sd_configs:
+      - role: pod
+      relabel_configs:
+      - action: keep
+        regex: \d+
+        source_labels:
+        - ` + securePortLabel + `
+      - action: replace
+        regex: ([^:]+)(?::\d+)?;(\d+)
+        replacement: $1:$2
+        source_labels:
+        - __address__
+        - ` + securePortLabel + `
+        target_label: __address__
+      - action: replace
+        source_labels:
+        - __meta_kubernetes_namespace
+        target_label: namespace
+      - action: replace
+        source_labels:
+        - __meta_kubernetes_pod_name
+        target_label: pod
+      - action: drop
+        regex: Pending|Succeeded|Failed|Completed
+        source_labels:
+        - __meta_kubernetes_pod_phase
+    - job_name: kubernetes-pods
+`,
+	},
 }
 
-func getPrometheusYaml() (string, error) {
+func getPrometheusYaml(cfg Config) (string, error) {
 	yamlBytes, err := os.ReadFile(filepath.Join(env.IstioSrc, "samples/addons/prometheus.yaml"))
 	if err != nil {
 		return "", err
 	}
+	scrapeInterval := cfg.ScrapeInterval
+	if scrapeInterval == 0 {
+		scrapeInterval = defaultScrapeInterval
+	}
+	// The scrape timeout cannot exceed the interval.
+	scrapeTimeout := defaultScrapeInterval
+	if scrapeInterval < scrapeTimeout {
+		scrapeTimeout = scrapeInterval
+	}
 	yaml := string(yamlBytes)
 	// For faster tests, drop scrape interval
-	yaml = strings.ReplaceAll(yaml, "scrape_interval: 15s", "scrape_interval: 5s")
-	yaml = strings.ReplaceAll(yaml, "scrape_timeout: 10s", "scrape_timeout: 5s")
+	yaml = strings.ReplaceAll(yaml, "scrape_interval: 15s", "scrape_interval: "+model.Duration(scrapeInterval).String())
+	yaml = strings.ReplaceAll(yaml, "scrape_timeout: 10s", "scrape_timeout: "+model.Duration(scrapeTimeout).String())
+	if len(cfg.RecordingRules) > 0 {
+		const emptyRules = "  recording_rules.yml: |\n    {}\n"
+		if !strings.Contains(yaml, emptyRules) {
+			return "", fmt.Errorf("failed to add recording rules: %q not found in sample", emptyRules)
+		}
+		yaml = strings.Replace(yaml, emptyRules, recordingRulesYaml(cfg.RecordingRules, scrapeInterval), 1)
+	}
+	if cfg.InMesh {
+		for _, r := range inMeshReplacements {
+			if !strings.Contains(yaml, r.old) {
+				return "", fmt.Errorf("failed to configure in-mesh Prometheus: %q not found in sample", r.old)
+			}
+			yaml = strings.Replace(yaml, r.old, r.new, 1)
+		}
+	}
 	return yaml, nil
 }
 
-func installPrometheus(ctx resource.Context, ns string) error {
-	yaml, err := getPrometheusYaml()
+// recordingRulesYaml returns the recording_rules.yml entry of the Prometheus ConfigMap for the given rules. The
+// rules are evaluated at every scrape, rather than at the much longer global evaluation interval.
+func recordingRulesYaml(rules map[string]string, interval time.Duration) string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	sb.WriteString("  recording_rules.yml: |\n")
+	sb.WriteString("    groups:\n")
+	sb.WriteString("    - name: istio-test\n")
+	sb.WriteString("      interval: " + model.Duration(interval).String() + "\n")
+	sb.WriteString("      rules:\n")
+	for _, name := range names {
+		sb.WriteString("      - record: " + name + "\n")
+		sb.WriteString("        expr: " + strconv.Quote(rules[name]) + "\n")
+	}
+	return sb.String()
+}
+
+func installPrometheus(ctx resource.Context, clusters cluster.Clusters, ns string, cfg Config) error {
+	yaml, err := getPrometheusYaml(cfg)
 	if err != nil {
 		return err
 	}
-	if err := ctx.ConfigKube().YAML(yaml).Apply(ns, resource.NoCleanup); err != nil {
+	if err := ctx.ConfigKube(clusters...).YAML(yaml).Apply(ns, resource.NoCleanup); err != nil {
 		return err
 	}
 	ctx.ConditionalCleanup(func() {
-		_ = ctx.ConfigKube().YAML(yaml).Delete(ns)
+		_ = ctx.ConfigKube(clusters...).YAML(yaml).Delete(ns)
 	})
 	return nil
 }
 
 func newKube(ctx resource.Context, cfgIn Config) (Instance, error) {
+	clusters := ctx.Clusters()
+	if cfgIn.Cluster != nil {
+		clusters = cluster.Clusters{cfgIn.Cluster}
+	}
 	c := &kubeComponent{
-		clusters: ctx.Clusters(),
+		clusters:       clusters,
+		scrapeInterval: cfgIn.ScrapeInterval,
+	}
+	if c.scrapeInterval == 0 {
+		c.scrapeInterval = defaultScrapeInterval
 	}
 	c.id = ctx.TrackResource(c)
 	c.api = make(map[string]prometheusApiV1.API)
@@ -95,11 +241,11 @@ func newKube(ctx resource.Context, cfgIn Config) (Instance, error) {
 	}
 
 	if !cfgIn.SkipDeploy {
-		if err := installPrometheus(ctx, cfg.TelemetryNamespace); err != nil {
+		if err := installPrometheus(ctx, clusters, cfg.TelemetryNamespace, cfgIn); err != nil {
 			return nil, err
 		}
 	}
-	for _, cls := range ctx.Clusters().Kube() {
+	for _, cls := range clusters.Kube() {
 		scopes.Framework.Debugf("Installing Prometheus on cluster: %s", cls.Name())
 		// Find the Prometheus pod and service, and start forwarding a local port.
 		fetchFn := testKube.NewSinglePodFetch(cls, cfg.TelemetryNamespace, fmt.Sprintf("app=%s", appName))
@@ -147,6 +293,10 @@ func (c *kubeComponent) API() prometheusApiV1.API {
 	return c.api[c.clusters.Default().Name()]
 }
 
+func (c *kubeComponent) Clusters() cluster.Clusters {
+	return c.clusters
+}
+
 func (c *kubeComponent) APIForCluster(cluster cluster.Cluster) prometheusApiV1.API {
 	return c.api[cluster.Name()]
 }
@@ -188,6 +338,165 @@ func (c *kubeComponent) QuerySum(cluster cluster.Cluster, query Query) (float64,
 	return got, nil
 }
 
+func (c *kubeComponent) QueryRule(cluster cluster.Cluster, name string) (float64, error) {
+	return c.QuerySum(cluster, Query{Metric: name})
+}
+
+func (c *kubeComponent) LabelsFor(cluster cluster.Cluster, metric string) ([]map[string]string, error) {
+	val, err := c.Query(cluster, Query{Metric: metric})
+	if err != nil {
+		return nil, err
+	}
+	vec, ok := val.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("value not a model.Vector; was %s", val.Type().String())
+	}
+	out := make([]map[string]string, 0, len(vec))
+	for _, sample := range vec {
+		labels := make(map[string]string, len(sample.Metric))
+		for k, v := range sample.Metric {
+			if k == model.MetricNameLabel {
+				continue
+			}
+			labels[string(k)] = string(v)
+		}
+		out = append(out, labels)
+	}
+	return out, nil
+}
+
+func (c *kubeComponent) QueryExpectEmpty(cluster cluster.Cluster, query Query) error {
+	v, _, err := c.api[cluster.Name()].Query(context.Background(), query.String(), time.Now())
+	if err != nil {
+		return fmt.Errorf("error querying Prometheus: %v", err)
+	}
+	if v.Type() != model.ValVector {
+		return fmt.Errorf("unhandled value type: %v", v.Type())
+	}
+	if value := v.(model.Vector); len(value) > 0 {
+		return fmt.Errorf("expected no samples for query %v, got %v", query, value)
+	}
+	return nil
+}
+
+func (c *kubeComponent) AssertBothReporters(cluster cluster.Cluster, labels map[string]string) error {
+	sums := map[string]float64{}
+	for _, reporter := range []string{"source", "destination"} {
+		query := Query{Metric: "istio_requests_total", Labels: map[string]string{"reporter": reporter}}
+		for k, v := range labels {
+			query.Labels[k] = v
+		}
+		sum, err := c.QuerySum(cluster, query)
+		if err != nil {
+			return fmt.Errorf("no metrics reported by %s: %v", reporter, err)
+		}
+		sums[reporter] = sum
+	}
+	if sums["source"] != sums["destination"] {
+		return fmt.Errorf("source reported %v requests, but destination reported %v", sums["source"], sums["destination"])
+	}
+	return nil
+}
+
+func (c *kubeComponent) QueryDelta(cluster cluster.Cluster, query Query, window time.Duration) (float64, error) {
+	query.Aggregation = ""
+	q := fmt.Sprintf("sum(increase(%s[%s]))", query, model.Duration(window))
+	return c.querySingle(cluster, q)
+}
+
+// querySingle runs a raw PromQL expression that evaluates to a single-element vector, and returns its value.
+func (c *kubeComponent) querySingle(cluster cluster.Cluster, q string) (float64, error) {
+	scopes.Framework.Debugf("Query running: %q", q)
+	v, _, err := c.api[cluster.Name()].Query(context.Background(), q, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("error querying Prometheus: %v", err)
+	}
+	vec, ok := v.(model.Vector)
+	if !ok {
+		return 0, fmt.Errorf("value not a model.Vector; was %s", v.Type().String())
+	}
+	if len(vec) == 0 {
+		return 0, fmt.Errorf("value not found (query: %v)", q)
+	}
+	return float64(vec[0].Value), nil
+}
+
+func (c *kubeComponent) QueryRate(cluster cluster.Cluster, query Query, window time.Duration) (float64, error) {
+	if window < c.scrapeInterval {
+		return 0, fmt.Errorf("rate window %v is smaller than the scrape interval %v", window, c.scrapeInterval)
+	}
+	query.Aggregation = ""
+	q := fmt.Sprintf("sum(rate(%s[%s]))", query, model.Duration(window))
+	return c.querySingle(cluster, q)
+}
+
+func (c *kubeComponent) QueryHistogramQuantile(cluster cluster.Cluster, query Query, quantile float64) (float64, error) {
+	query.Aggregation = ""
+	if !strings.HasSuffix(query.Metric, "_bucket") {
+		query.Metric += "_bucket"
+	}
+	// Cover several scrapes, so that rate() has enough samples even right after traffic was sent.
+	window := time.Minute
+	if w := 4 * c.scrapeInterval; w > window {
+		window = w
+	}
+	q := fmt.Sprintf("histogram_quantile(%v, sum(rate(%s[%s])) by (le))", quantile, query, model.Duration(window))
+	v, err := c.querySingle(cluster, q)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) {
+		return 0, fmt.Errorf("no observations for %v in the last %v", query, window)
+	}
+	return v, nil
+}
+
+func (c *kubeComponent) WaitForScrapeCount(cluster cluster.Cluster, target string, n int) error {
+	// The timestamp of the up series is the time of the last scrape of the target.
+	q := fmt.Sprintf("max(timestamp(up{pod=%q}))", target)
+	var last model.SampleValue
+	scrapes := -1
+	return retry.UntilSuccess(func() error {
+		v, _, err := c.api[cluster.Name()].Query(context.Background(), q, time.Now())
+		if err != nil {
+			return fmt.Errorf("error querying Prometheus: %v", err)
+		}
+		vec, ok := v.(model.Vector)
+		if !ok || len(vec) == 0 {
+			return fmt.Errorf("no scrapes found for %s", target)
+		}
+		if ts := vec[0].Value; ts != last {
+			last = ts
+			// The first observed scrape may have happened before the call.
+			scrapes++
+		}
+		if scrapes < n {
+			return fmt.Errorf("observed %d/%d scrapes of %s", scrapes, n, target)
+		}
+		return nil
+	}, retry.Delay(time.Second), retry.Timeout(time.Duration(n+1)*time.Minute))
+}
+
+func (c *kubeComponent) AssertStable(cluster cluster.Cluster, query Query, window time.Duration, tolerance float64) error {
+	minV, maxV := math.Inf(1), math.Inf(-1)
+	deadline := time.Now().Add(window)
+	for {
+		v, err := c.QuerySum(cluster, query)
+		if err != nil {
+			return err
+		}
+		minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+		if maxV-minV > tolerance {
+			return fmt.Errorf("%v varied from %v to %v within %v, more than the tolerance of %v", query, minV, maxV, window, tolerance)
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+		// Sampling more often than Prometheus scrapes would only return the same values.
+		time.Sleep(c.scrapeInterval)
+	}
+}
+
 func Sum(val model.Value) (float64, error) {
 	if val.Type() != model.ValVector {
 		return 0, fmt.Errorf("value not a model.Vector; was %s", val.Type().String())
@@ -214,10 +523,13 @@ func (c *kubeComponent) Close() error {
 	return nil
 }
 
+// Query is a PromQL instant vector selector, optionally wrapped in an aggregation.
 type Query struct {
 	Metric      string
 	Aggregation string
-	Labels      map[string]string
+	// Labels that the series must have. As with any PromQL selector, this is a subset match: series with
+	// additional labels also match, so labels that are irrelevant to the test can be left out.
+	Labels map[string]string
 }
 
 func (q Query) String() string {
@@ -0,0 +1,76 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestGetPrometheusYamlInMesh(t *testing.T) {
+	out, err := getPrometheusYaml(Config{InMesh: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var promConfig struct {
+		ScrapeConfigs []struct {
+			JobName   string `json:"job_name"`
+			Scheme    string `json:"scheme"`
+			TLSConfig struct {
+				CertFile string `json:"cert_file"`
+			} `json:"tls_config"`
+		} `json:"scrape_configs"`
+	}
+	found := false
+	for _, doc := range strings.Split(out, "\n---\n") {
+		var cm struct {
+			Kind string            `json:"kind"`
+			Data map[string]string `json:"data"`
+		}
+		if err := yaml.Unmarshal([]byte(doc), &cm); err != nil {
+			t.Fatalf("invalid YAML: %v\n%s", err, doc)
+		}
+		if cm.Kind != "ConfigMap" || cm.Data["prometheus.yml"] == "" {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(cm.Data["prometheus.yml"]), &promConfig); err != nil {
+			t.Fatalf("invalid prometheus.yml: %v", err)
+		}
+		found = true
+	}
+	if !found {
+		t.Fatal("no prometheus.yml found")
+	}
+
+	for _, job := range promConfig.ScrapeConfigs {
+		if job.JobName != SecureScrapeJob {
+			continue
+		}
+		if job.Scheme != "https" {
+			t.Fatalf("expected %s to scrape over https, got scheme %q", SecureScrapeJob, job.Scheme)
+		}
+		if job.TLSConfig.CertFile != "/etc/prom-certs/cert-chain.pem" {
+			t.Fatalf("expected %s to use the mesh certificates, got cert_file %q", SecureScrapeJob, job.TLSConfig.CertFile)
+		}
+		if !strings.Contains(out, `sidecar.istio.io/inject: "true"`) || !strings.Contains(out, "OUTPUT_CERTS") {
+			t.Fatal("expected Prometheus to be injected with a sidecar that outputs its certificates")
+		}
+		return
+	}
+	t.Fatalf("no %s job found", SecureScrapeJob)
+}
@@ -15,14 +15,31 @@
 package prometheus
 
 import (
+	"fmt"
+	"time"
+
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	prom "github.com/prometheus/common/model"
 
 	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/echo"
+	"istio.io/istio/pkg/test/echo/check"
 	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/resource"
 )
 
+const (
+	// SecurePortAnnotation is the pod annotation naming the port that a Prometheus deployed with Config.InMesh
+	// scrapes over mTLS. The port must be intercepted by the sidecar of the pod.
+	SecurePortAnnotation = "prometheus.test.istio.io/secure-port"
+
+	// SecureScrapeJob is the job of the series scraped over mTLS, for pods annotated with SecurePortAnnotation.
+	SecureScrapeJob = "kubernetes-pods-istio-secure"
+
+	// securePortLabel is the Kubernetes service discovery label holding the value of SecurePortAnnotation.
+	securePortLabel = "__meta_kubernetes_pod_annotation_prometheus_test_istio_io_secure_port"
+)
+
 type Instance interface {
 	resource.Resource
 
@@ -30,16 +47,93 @@ type Instance interface {
 	API() v1.API
 	APIForCluster(cluster cluster.Cluster) v1.API
 
+	// Clusters returns the clusters in which this instance queries Prometheus.
+	Clusters() cluster.Clusters
+
 	// Query Run the provided query against the given cluster
 	Query(cluster cluster.Cluster, query Query) (prom.Value, error)
 
 	// QuerySum is a help around Query to compute the sum
 	QuerySum(cluster cluster.Cluster, query Query) (float64, error)
+
+	// QueryRule returns the current value of the series recorded by the recording rule with the given name,
+	// summed across series. The rules are loaded through Config.RecordingRules.
+	QueryRule(cluster cluster.Cluster, name string) (float64, error)
+
+	// LabelsFor returns the label sets of all series of the given metric in the given cluster, without the
+	// metric name. This helps finding the labels to use in a Query, or why a Query does not match.
+	LabelsFor(cluster cluster.Cluster, metric string) ([]map[string]string, error)
+
+	// QueryExpectEmpty runs the provided query against the given cluster, and returns an error if it
+	// returns any samples. Series that already exist stay around after a metric is disabled, so the query
+	// should only match series that were not produced before the change under test.
+	QueryExpectEmpty(cluster cluster.Cluster, query Query) error
+
+	// AssertBothReporters verifies that istio_requests_total series matching the given labels are reported by
+	// both the source and the destination proxies, with the same total value. Since the two reporters are
+	// scraped independently, callers should retry on failure.
+	AssertBothReporters(cluster cluster.Cluster, labels map[string]string) error
+
+	// QueryDelta returns how much the counters matching the query increased over the given window, summed
+	// across series. Like PromQL increase(), which it uses, counter resets such as those caused by a proxy
+	// restart are accounted for rather than producing negative deltas. The query's Aggregation is ignored.
+	QueryDelta(cluster cluster.Cluster, query Query, window time.Duration) (float64, error)
+
+	// QueryRate returns the per-second rate of the counters matching the query over the given window, summed
+	// across series, using PromQL rate(). The window must not be smaller than the scrape interval, as rate()
+	// needs at least two samples per series. The query's Aggregation is ignored.
+	QueryRate(cluster cluster.Cluster, query Query, window time.Duration) (float64, error)
+
+	// QueryHistogramQuantile returns the given quantile (e.g. 0.99) of the histogram matching the query over the
+	// last minute, such as istio_request_duration_milliseconds. The "_bucket" suffix is added to the metric if
+	// missing, and the buckets of all matching series are summed. The query's Aggregation is ignored.
+	QueryHistogramQuantile(cluster cluster.Cluster, query Query, quantile float64) (float64, error)
+
+	// AssertStable samples the sum of the query repeatedly over the given window, and returns an error as soon as
+	// the sampled values differ by more than tolerance. This catches values that flap or keep growing while no
+	// traffic is sent, which a single query cannot detect.
+	AssertStable(cluster cluster.Cluster, query Query, window time.Duration, tolerance float64) error
+
+	// WaitForScrapeCount waits until Prometheus in the given cluster has scraped the pod with the given name
+	// n more times, so that metrics produced by traffic sent before the call are guaranteed to be visible.
+	WaitForScrapeCount(cluster cluster.Cluster, target string, n int) error
 }
 
 type Config struct {
 	// If true, connect to an existing prometheus rather than creating a new one
 	SkipDeploy bool
+
+	// ScrapeInterval is the interval at which the deployed Prometheus scrapes its targets. Shorter intervals
+	// make metrics visible sooner. If not set, defaults to 5 seconds.
+	ScrapeInterval time.Duration
+
+	// Cluster, if set, restricts the instance to the Prometheus of the given cluster. Otherwise, the instance
+	// covers all clusters.
+	Cluster cluster.Cluster
+
+	// InMesh deploys Prometheus with a sidecar that provisions it with mesh certificates, without intercepting
+	// any of its traffic. Pods annotated with SecurePortAnnotation are then scraped over mTLS by the
+	// SecureScrapeJob, in addition to the plaintext scraping of the sample configuration.
+	InMesh bool
+
+	// RecordingRules to load into the deployed Prometheus, keyed by the name of the recorded series, with the
+	// PromQL expressions to record as values. The rules are evaluated at the scrape interval.
+	RecordingRules map[string]string
+}
+
+// Instances is a set of Prometheus instances, typically one per cluster.
+type Instances []Instance
+
+// ForCluster returns the instance that queries the Prometheus of the given cluster, or nil if there is none.
+func (i Instances) ForCluster(c cluster.Cluster) Instance {
+	for _, inst := range i {
+		for _, ic := range inst.Clusters() {
+			if ic.Name() == c.Name() {
+				return inst
+			}
+		}
+	}
+	return nil
 }
 
 // New returns a new instance of prometheus.
@@ -47,6 +141,20 @@ func New(ctx resource.Context, c Config) (i Instance, err error) {
 	return newKube(ctx, c)
 }
 
+// NewPerCluster returns a new Prometheus instance for each cluster, each restricted to its own cluster.
+func NewPerCluster(ctx resource.Context, c Config) (Instances, error) {
+	var out Instances
+	for _, cls := range ctx.Clusters().Kube() {
+		c.Cluster = cls
+		i, err := New(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, i)
+	}
+	return out, nil
+}
+
 // NewOrFail returns a new Prometheus instance or fails test.
 func NewOrFail(t test.Failer, ctx resource.Context, c Config) Instance {
 	t.Helper()
@@ -57,3 +165,35 @@ func NewOrFail(t test.Failer, ctx resource.Context, c Config) Instance {
 
 	return i
 }
+
+// UsedPassthroughCluster returns a checker that verifies that calls from sourceWorkload went through the
+// PassthroughCluster, by checking that the given metric (e.g. istio_requests_total for HTTP or
+// istio_tcp_connections_opened_total for TCP) reported by the source with destination_service_name set to
+// PassthroughCluster increased. The baseline is read when the checker is created, so it must be created right
+// before the call, with retries enabled so that the metric has time to be scraped.
+func UsedPassthroughCluster(p Instance, c cluster.Cluster, metric, sourceWorkload string) check.Checker {
+	query := Query{
+		Metric:      metric,
+		Aggregation: "sum",
+		Labels: map[string]string{
+			"reporter":                 "source",
+			"source_workload":          sourceWorkload,
+			"destination_service_name": "PassthroughCluster",
+		},
+	}
+	// No series are reported before the first request through the PassthroughCluster.
+	before, _ := p.QuerySum(c, query)
+	return func(_ echo.Responses, err error) error {
+		if err != nil {
+			return err
+		}
+		after, err := p.QuerySum(c, query)
+		if err != nil {
+			return err
+		}
+		if after <= before {
+			return fmt.Errorf("%s did not increase from %v, the requests did not use the PassthroughCluster", query, before)
+		}
+		return nil
+	}
+}
@@ -15,12 +15,23 @@
 package framework
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kubeRuntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
 
+	"istio.io/api/label"
+	"istio.io/istio/istioctl/cmd"
+	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/components/istioctl"
@@ -28,6 +39,7 @@ import (
 	"istio.io/istio/pkg/test/scopes"
 	"istio.io/istio/pkg/test/util/file"
 	"istio.io/istio/pkg/test/util/tmpl"
+	"istio.io/istio/pkg/test/util/yml"
 )
 
 var _ resource.ConfigManager = &configManager{}
@@ -36,6 +48,7 @@ type configManager struct {
 	ctx      resource.Context
 	clusters []cluster.Cluster
 	prefix   string
+	revision string
 }
 
 func newConfigManager(ctx resource.Context, clusters cluster.Clusters) resource.ConfigManager {
@@ -76,13 +89,41 @@ func (c *configManager) File(filePaths ...string) resource.Config {
 	}
 }
 
-func (c *configManager) EvalFile(args interface{}, filePaths ...string) resource.Config {
-	yamlTemplates, err := file.AsStringArray(filePaths...)
+func (c *configManager) Objects(objs ...kubeRuntime.Object) resource.Config {
+	yamlText := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		text, err := objectToYAML(obj)
+		if err != nil {
+			panic(err)
+		}
+		yamlText = append(yamlText, text)
+	}
+	return c.YAML(yamlText...)
+}
+
+func objectToYAML(obj kubeRuntime.Object) (string, error) {
+	kinds, _, err := kube.IstioScheme.ObjectKinds(obj)
 	if err != nil {
-		panic(err)
+		return "", err
+	}
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(kinds[0])
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed marshaling %v: %v", kinds[0], err)
 	}
+	return string(out), nil
+}
 
-	yamlText := tmpl.MustEvaluateAll(args, yamlTemplates...)
+func (c *configManager) EvalFile(args interface{}, filePaths ...string) resource.Config {
+	yamlText := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		text, err := evalFiles.eval(args, filePath)
+		if err != nil {
+			panic(err)
+		}
+		yamlText = append(yamlText, text)
+	}
 
 	return &yamlConfig{
 		configManager: c,
@@ -91,12 +132,100 @@ func (c *configManager) EvalFile(args interface{}, filePaths ...string) resource
 	}
 }
 
+// evalFiles caches the files templated by EvalFile. ConfigIstio() and ConfigKube() return a new config manager
+// on every call, so the cache is shared by all of them.
+// Note: go tests are distinct binaries per test suite, so this is a suite level cache
+var evalFiles = newEvalFileCache()
+
+// evalFileCache caches the result of templating a file with a given set of arguments, since suites often
+// apply the same templated file many times (e.g. per subtest). Entries are keyed on the path, modification
+// time and size of the file and the printed arguments, so the file is only read again once it is edited.
+type evalFileCache struct {
+	sync.Mutex
+	entries map[string]string
+}
+
+func newEvalFileCache() *evalFileCache {
+	return &evalFileCache{entries: map[string]string{}}
+}
+
+func (e *evalFileCache) eval(args interface{}, filePath string) (string, error) {
+	if !cacheableArgs(reflect.ValueOf(args)) {
+		return evalFile(args, filePath)
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("%s\x00%d\x00%d\x00%#v", filePath, info.ModTime().UnixNano(), info.Size(), args)
+	e.Lock()
+	defer e.Unlock()
+	if text, ok := e.entries[key]; ok {
+		return text, nil
+	}
+	text, err := evalFile(args, filePath)
+	if err != nil {
+		return "", err
+	}
+	e.entries[key] = text
+	return text, nil
+}
+
+func evalFile(args interface{}, filePath string) (string, error) {
+	yamlTemplate, err := file.AsString(filePath)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Evaluate(yamlTemplate, args)
+}
+
+// cacheableArgs returns false if the value holds pointers, or other references whose target may change
+// without changing how the value prints.
+func cacheableArgs(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.UnsafePointer, reflect.Func, reflect.Chan:
+		return false
+	case reflect.Interface:
+		return v.IsNil() || cacheableArgs(v.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !cacheableArgs(v.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if !cacheableArgs(iter.Key()) || !cacheableArgs(iter.Value()) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !cacheableArgs(v.Field(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (c *configManager) applyYAML(cleanup bool, ns string, yamlText ...string) error {
 	if len(c.prefix) == 0 {
 		return c.WithFilePrefix("apply").(*configManager).applyYAML(cleanup, ns, yamlText...)
 	}
 	GlobalYAMLWrites.Add(uint64(len(yamlText)))
 
+	if c.revision != "" {
+		var err error
+		if yamlText, err = withRevisionLabel(c.revision, yamlText...); err != nil {
+			return err
+		}
+	}
+
 	// Convert the content to files.
 	yamlFiles, err := c.ctx.WriteYAML(c.prefix, yamlText...)
 	if err != nil {
@@ -174,12 +303,107 @@ func (c *configManager) WaitForConfigOrFail(ctx resource.Context, t test.Failer,
 	}
 }
 
+// validate checks that "istioctl analyze" reports no errors for the Istio resources in the given YAML. Unlike the
+// status of the resources, which istiod only populates when status and analysis are enabled, analysis reads the
+// config from each cluster directly, so it neither depends on the install nor needs to wait for istiod.
+func (c *configManager) validate(ns string, yamlText ...string) error {
+	origins := map[string]struct{}{}
+	namespaces := map[string]struct{}{}
+	for _, text := range yamlText {
+		for _, doc := range yml.SplitString(text) {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+				return err
+			}
+			if !strings.HasSuffix(obj.GroupVersionKind().Group, "istio.io") {
+				continue
+			}
+			objNs := obj.GetNamespace()
+			if objNs == "" {
+				objNs = ns
+			}
+			// Analysis messages refer to resources as "<kind> <namespace>/<name>".
+			origins[fmt.Sprintf("%s %s/%s", obj.GetKind(), objNs, obj.GetName())] = struct{}{}
+			namespaces[objNs] = struct{}{}
+		}
+	}
+
+	for _, cl := range c.clusters {
+		ik, err := istioctl.New(c.ctx, istioctl.Config{Cluster: cl})
+		if err != nil {
+			return err
+		}
+		for objNs := range namespaces {
+			stdout, stderr, err := ik.Invoke([]string{"analyze", "--use-kube=true", "-ojson", "--namespace", objNs})
+			// analyze returns an error if it found any issues, which are reported as messages instead.
+			if err != nil && !errors.As(err, &cmd.AnalyzerFoundIssuesError{}) {
+				return fmt.Errorf("istioctl analyze failed in cluster %s: %v\nerr: %v", cl.Name(), err, stderr)
+			}
+			var msgs []struct {
+				Code    string `json:"code"`
+				Level   string `json:"level"`
+				Origin  string `json:"origin"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &msgs); err != nil {
+				return fmt.Errorf("failed parsing analysis output %q: %v", stdout, err)
+			}
+			for _, m := range msgs {
+				if _, f := origins[m.Origin]; f && m.Level == "Error" {
+					return fmt.Errorf("%s rejected in cluster %s: %s %s", m.Origin, cl.Name(), m.Code, m.Message)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (c *configManager) WithFilePrefix(prefix string) resource.ConfigManager {
 	return &configManager{
 		ctx:      c.ctx,
 		prefix:   prefix,
 		clusters: c.clusters,
+		revision: c.revision,
+	}
+}
+
+func (c *configManager) WithRevisionLabel(revision string) resource.ConfigManager {
+	return &configManager{
+		ctx:      c.ctx,
+		prefix:   c.prefix,
+		clusters: c.clusters,
+		revision: revision,
+	}
+}
+
+// withRevisionLabel sets the istio.io/rev label on each of the resources in the given YAML.
+func withRevisionLabel(revision string, yamlText ...string) ([]string, error) {
+	out := make([]string, 0, len(yamlText))
+	for _, text := range yamlText {
+		var docs []string
+		for _, doc := range yml.SplitString(text) {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+				return nil, err
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+			labels := obj.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[label.IoIstioRev.Name] = revision
+			obj.SetLabels(labels)
+			by, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, string(by))
+		}
+		out = append(out, yml.JoinString(docs...))
 	}
+	return out, nil
 }
 
 var _ resource.Config = &yamlConfig{}
@@ -216,6 +440,12 @@ func (c *yamlConfig) Apply(ns string, opts ...resource.ConfigOption) error {
 				"failed waiting for YAML %v: %v", c.contentForError(), err)
 		}
 	}
+
+	if options.Validate {
+		if err := c.validate(ns, c.yamlText...); err != nil {
+			return fmt.Errorf("failed validating YAML %v: %v", c.contentForError(), err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,69 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/test/framework/components/cluster"
+)
+
+func TestEvalFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	mtime := time.Now()
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		// Pin the modification time, so that the cache only notices edits that change the size.
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// ConfigIstio() and ConfigKube() return a new config manager on every call.
+	clusters := cluster.Clusters{cluster.NewFake("fake", "1", "23")}
+	evalFile := func(args interface{}, expected string) {
+		t.Helper()
+		got := newConfigManager(nil, clusters).EvalFile(args, path).(*yamlConfig).yamlText
+		if len(got) != 1 || got[0] != expected {
+			t.Fatalf("expected %q, got %q", expected, got)
+		}
+	}
+
+	write("name: {{ .Name }}")
+	evalFile(map[string]string{"Name": "a"}, "name: a")
+	evalFile(map[string]string{"Name": "b"}, "name: b")
+
+	// The file is not read again while it is unmodified, even from another config manager.
+	write("eman: {{ .Name }}")
+	evalFile(map[string]string{"Name": "a"}, "name: a")
+
+	// Edits to the file are picked up.
+	mtime = mtime.Add(time.Second)
+	write("eman: {{ .Name }}")
+	evalFile(map[string]string{"Name": "a"}, "eman: a")
+	write("changed: {{ .Name }}")
+	evalFile(map[string]string{"Name": "a"}, "changed: a")
+
+	// Pointer args may be mutated between calls, so they are not cached.
+	args := &struct{ Name string }{Name: "a"}
+	evalFile(args, "changed: a")
+	args.Name = "b"
+	evalFile(args, "changed: b")
+}
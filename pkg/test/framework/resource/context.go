@@ -15,6 +15,8 @@
 package resource
 
 import (
+	kubeRuntime "k8s.io/apimachinery/pkg/runtime"
+
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/util/yml"
@@ -23,6 +25,7 @@ import (
 type ConfigOptions struct {
 	NoCleanup bool
 	Wait      bool
+	Validate  bool
 }
 
 type ConfigOption func(o *ConfigOptions)
@@ -37,6 +40,12 @@ var Wait ConfigOption = func(o *ConfigOptions) {
 	o.Wait = true
 }
 
+// Validate the Config once applied, failing if "istioctl analyze" reports errors for any of the applied Istio
+// resources.
+var Validate ConfigOption = func(o *ConfigOptions) {
+	o.Validate = true
+}
+
 // Config that can be applied or deleted on the clusters contained within a ConfigManager.
 type Config interface {
 	// Apply this config to all clusters within the ConfigManager
@@ -62,8 +71,42 @@ type ConfigManager interface {
 	// EvalFile the same as File, but it evaluates the template parameters.
 	EvalFile(args interface{}, paths ...string) Config
 
+	// Objects creates a Config from the given typed objects (e.g. Istio client-go types). The apiVersion and
+	// kind are filled in from the object's type, so they do not need to be set.
+	Objects(objs ...kubeRuntime.Object) Config
+
 	// WithFilePrefix sets the prefix used for intermediate files.
 	WithFilePrefix(prefix string) ConfigManager
+
+	// WithRevisionLabel sets the istio.io/rev label to the given revision on all resources applied through
+	// the returned ConfigManager, so that they are processed by that revision's control plane.
+	WithRevisionLabel(revision string) ConfigManager
+
+	// HeaderRoute creates a VirtualService for host that routes requests with the given header value to
+	// destSubset. All other requests are routed to host without a subset.
+	HeaderRoute(host, header, value, destSubset string) Config
+
+	// PrefixRewrite creates a VirtualService for host that rewrites the matchPrefix of request paths to
+	// rewritePrefix and routes the requests to dest.
+	PrefixRewrite(host, matchPrefix, rewritePrefix, dest string) Config
+
+	// RetryPolicy creates a VirtualService for host that retries failed requests up to attempts times,
+	// under the conditions given by retryOn (e.g. "5xx,reset").
+	RetryPolicy(host string, attempts int, retryOn string) Config
+
+	// Telemetry creates a Telemetry resource with the given name that adds the given dimensions to the
+	// Prometheus istio_requests_total metric. Dimension values are CEL expressions, such as
+	// "request.headers['x-custom']". New dimensions must also be listed in the sidecar.istio.io/extraStatTags
+	// annotation of the workloads reporting them.
+	Telemetry(name string, dimensions map[string]string) Config
+
+	// DisableMetric creates a Telemetry resource that disables the given standard metric (e.g. REQUEST_COUNT)
+	// for the Prometheus provider.
+	DisableMetric(metric string) Config
+
+	// RemoveMetricTags creates a Telemetry resource that removes the given tags (e.g. source_workload) from the
+	// given standard metric for the Prometheus provider.
+	RemoveMetricTags(metric string, tags ...string) Config
 }
 
 // Context is the core context interface that is used by resources.
@@ -0,0 +1,114 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"strings"
+
+	"istio.io/istio/pkg/test/framework/resource"
+	"istio.io/istio/pkg/test/util/tmpl"
+)
+
+const headerRouteTemplate = `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: {{ .Name }}
+spec:
+  hosts:
+  - {{ .Host }}
+  http:
+  - match:
+    - headers:
+        {{ .Header }}:
+          exact: {{ .Value }}
+    route:
+    - destination:
+        host: {{ .Host }}
+        subset: {{ .Subset }}
+  - route:
+    - destination:
+        host: {{ .Host }}
+`
+
+func (c *configManager) HeaderRoute(host, header, value, destSubset string) resource.Config {
+	return c.YAML(tmpl.MustEvaluateStrict(headerRouteTemplate, map[string]string{
+		"Name":   routeName("header", host),
+		"Host":   host,
+		"Header": strings.ToLower(header),
+		"Value":  value,
+		"Subset": destSubset,
+	}))
+}
+
+const prefixRewriteTemplate = `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: {{ .Name }}
+spec:
+  hosts:
+  - {{ .Host }}
+  http:
+  - match:
+    - uri:
+        prefix: {{ .Match }}
+    rewrite:
+      uri: {{ .Rewrite }}
+    route:
+    - destination:
+        host: {{ .Dest }}
+`
+
+func (c *configManager) PrefixRewrite(host, matchPrefix, rewritePrefix, dest string) resource.Config {
+	return c.YAML(tmpl.MustEvaluateStrict(prefixRewriteTemplate, map[string]string{
+		"Name":    routeName("rewrite", host),
+		"Host":    host,
+		"Match":   matchPrefix,
+		"Rewrite": rewritePrefix,
+		"Dest":    dest,
+	}))
+}
+
+const retryPolicyTemplate = `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: {{ .Name }}
+spec:
+  hosts:
+  - {{ .Host }}
+  http:
+  - route:
+    - destination:
+        host: {{ .Host }}
+    retries:
+      attempts: {{ .Attempts }}
+      retryOn: {{ .RetryOn }}
+`
+
+func (c *configManager) RetryPolicy(host string, attempts int, retryOn string) resource.Config {
+	return c.YAML(tmpl.MustEvaluateStrict(retryPolicyTemplate, map[string]interface{}{
+		"Name":     routeName("retry", host),
+		"Host":     host,
+		"Attempts": attempts,
+		"RetryOn":  retryOn,
+	}))
+}
+
+// routeName generates a VirtualService name for the given kind of route to host.
+func routeName(kind, host string) string {
+	return kind + "-route-" + strings.ToLower(strings.ReplaceAll(host, ".", "-"))
+}
@@ -0,0 +1,97 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"strings"
+
+	"istio.io/istio/pkg/test/framework/resource"
+	"istio.io/istio/pkg/test/util/tmpl"
+)
+
+const telemetryTemplate = `
+apiVersion: telemetry.istio.io/v1alpha1
+kind: Telemetry
+metadata:
+  name: {{ .Name }}
+spec:
+  metrics:
+  - providers:
+    - name: prometheus
+    overrides:
+    - match:
+        metric: REQUEST_COUNT
+      tagOverrides:
+{{- range $dimension, $value := .Dimensions }}
+        {{ $dimension }}:
+          value: {{ printf "%q" $value }}
+{{- end }}
+`
+
+func (c *configManager) Telemetry(name string, dimensions map[string]string) resource.Config {
+	return c.YAML(tmpl.MustEvaluateStrict(telemetryTemplate, map[string]interface{}{
+		"Name":       name,
+		"Dimensions": dimensions,
+	}))
+}
+
+const disableMetricTemplate = `
+apiVersion: telemetry.istio.io/v1alpha1
+kind: Telemetry
+metadata:
+  name: {{ .Name }}
+spec:
+  metrics:
+  - providers:
+    - name: prometheus
+    overrides:
+    - match:
+        metric: {{ .Metric }}
+      disabled: true
+`
+
+func (c *configManager) DisableMetric(metric string) resource.Config {
+	return c.YAML(tmpl.MustEvaluateStrict(disableMetricTemplate, map[string]string{
+		"Name":   "disable-" + strings.ToLower(strings.ReplaceAll(metric, "_", "-")),
+		"Metric": metric,
+	}))
+}
+
+const removeMetricTagsTemplate = `
+apiVersion: telemetry.istio.io/v1alpha1
+kind: Telemetry
+metadata:
+  name: {{ .Name }}
+spec:
+  metrics:
+  - providers:
+    - name: prometheus
+    overrides:
+    - match:
+        metric: {{ .Metric }}
+      tagOverrides:
+{{- range .Tags }}
+        {{ . }}:
+          operation: REMOVE
+{{- end }}
+`
+
+func (c *configManager) RemoveMetricTags(metric string, tags ...string) resource.Config {
+	return c.YAML(tmpl.MustEvaluateStrict(removeMetricTagsTemplate, map[string]interface{}{
+		"Name":   "remove-tags-" + strings.ToLower(strings.ReplaceAll(metric, "_", "-")),
+		"Metric": metric,
+		"Tags":   tags,
+	}))
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/features"
 	"istio.io/istio/pkg/test/framework/label"
 	"istio.io/istio/pkg/test/framework/resource"
@@ -97,6 +98,9 @@ type Test interface {
 	// T1a and T1b are run asynchronously with each other. After T1a and T1b complete, T2 is then run in the
 	// same way: T2 exits, then T2a and T2b are run asynchronously to completion.
 	RunParallel(fn func(t TestContext))
+	// PerCluster runs the test, executing fn in a subtest named "From <cluster>" for each cluster in the
+	// test context. This is intended for tests that use each cluster in turn as the source of traffic.
+	PerCluster(fn func(t TestContext, c cluster.Cluster))
 }
 
 // Test allows the test author to specify test-related metadata in a fluent-style, before commencing execution.
@@ -207,6 +211,17 @@ func (t *testImpl) RunParallel(fn func(ctx TestContext)) {
 	t.runInternal(fn, true)
 }
 
+func (t *testImpl) PerCluster(fn func(ctx TestContext, c cluster.Cluster)) {
+	t.Run(func(ctx TestContext) {
+		for _, c := range ctx.Clusters() {
+			c := c
+			ctx.NewSubTest(fmt.Sprintf("From %s", c.StableName())).Run(func(ctx TestContext) {
+				fn(ctx, c)
+			})
+		}
+	})
+}
+
 func (t *testImpl) runInternal(fn func(ctx TestContext), parallel bool) {
 	// Disallow running the same test more than once.
 	if t.ctx != nil {
@@ -49,6 +49,26 @@ func MustEvaluate(tpl string, data interface{}) string {
 	return s
 }
 
+// EvaluateStrict behaves like Evaluate, but returns an error if the template references a key that is
+// missing from the given parameters, rather than rendering "<no value>".
+func EvaluateStrict(tpl string, data interface{}) (string, error) {
+	t, err := Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+
+	return Execute(t.Option("missingkey=error"), data)
+}
+
+// MustEvaluateStrict calls EvaluateStrict and panics if there is an error.
+func MustEvaluateStrict(tpl string, data interface{}) string {
+	s, err := EvaluateStrict(tpl, data)
+	if err != nil {
+		panic(fmt.Sprintf("tmpl.MustEvaluateStrict: %v", err))
+	}
+	return s
+}
+
 // EvaluateAll calls Evaluate the same data args against each of the given templates.
 func EvaluateAll(data interface{}, templates ...string) ([]string, error) {
 	out := make([]string, 0, len(templates))
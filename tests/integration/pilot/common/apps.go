@@ -21,19 +21,27 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/common"
 	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
+	"istio.io/istio/pkg/test/framework/components/echo/util/traffic"
 	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/pkg/test/framework/components/istio/ingress"
 	"istio.io/istio/pkg/test/framework/components/namespace"
 	"istio.io/istio/pkg/test/framework/resource"
-	"istio.io/istio/pkg/test/util/tmpl"
 )
 
+// restartTrafficInterval is the interval between requests sent by RestartWithoutDrops, short enough to
+// catch connections dropped during the restart.
+const restartTrafficInterval = 100 * time.Millisecond
+
 type EchoDeployments struct {
 	// Namespace echo apps will be deployed
 	Namespace namespace.Instance
@@ -87,13 +95,10 @@ const (
 	externalHostname = "fake.external.com"
 )
 
+// FindPortByName returns the port in common.EchoPorts with the given name, or an empty port if none exists.
 func FindPortByName(name string) echo.Port {
-	for _, p := range common.EchoPorts {
-		if p.Name == name {
-			return p
-		}
-	}
-	return echo.Port{}
+	p, _ := echo.Config{Ports: common.EchoPorts}.Port(name)
+	return p
 }
 
 func serviceEntryPorts() []echo.Port {
@@ -296,32 +301,24 @@ spec:
 		return err
 	}
 
-	se, err := tmpl.Evaluate(`apiVersion: networking.istio.io/v1alpha3
-kind: ServiceEntry
-metadata:
-  name: external-service
-spec:
-  hosts:
-  - {{.Hostname}}
-  location: MESH_EXTERNAL
-  resolution: DNS
-  endpoints:
-  - address: external.{{.Namespace}}.svc.cluster.local
-  ports:
-  - name: http-tls-origination
-    number: 8888
-    protocol: http
-    targetPort: 443
-  - name: http2-tls-origination
-    number: 8882
-    protocol: http2
-    targetPort: 443
-{{- range $i, $p := .Ports }}
-  - name: {{$p.Name}}
-    number: {{$p.ServicePort}}
-    protocol: "{{$p.Protocol}}"
-{{- end }}
-`, map[string]interface{}{"Namespace": apps.ExternalNamespace.Name(), "Hostname": externalHostname, "Ports": serviceEntryPorts()})
+	se, err := echo.ServiceEntryFor(apps.External[0], echo.ServiceEntryOptions{
+		Name:  "external-service",
+		Hosts: []string{externalHostname},
+		Ports: append([]echo.Port{
+			{
+				Name:        "http-tls-origination",
+				Protocol:    protocol.HTTP,
+				ServicePort: 8888,
+				TargetPort:  443,
+			},
+			{
+				Name:        "http2-tls-origination",
+				Protocol:    protocol.HTTP2,
+				ServicePort: 8882,
+				TargetPort:  443,
+			},
+		}, serviceEntryPorts()...),
+	})
 	if err != nil {
 		return err
 	}
@@ -335,6 +332,18 @@ func (d EchoDeployments) IsMulticluster() bool {
 	return d.All.Clusters().IsMulticluster()
 }
 
+// NakedClient returns the Naked (out-of-mesh) echo instance in the given cluster, to be used as the
+// source of calls originating outside the mesh.
+func (d EchoDeployments) NakedClient(c cluster.Cluster) (echo.Instance, error) {
+	return d.Naked.Get(echo.InCluster(c))
+}
+
+// NakedClientOrFail calls NakedClient and fails the test if no Naked instance is found.
+func (d EchoDeployments) NakedClientOrFail(t test.Failer, c cluster.Cluster) echo.Instance {
+	t.Helper()
+	return d.Naked.GetOrFail(t, echo.InCluster(c))
+}
+
 // Restart restarts all echo deployments.
 func (d EchoDeployments) Restart() error {
 	wg := sync.WaitGroup{}
@@ -359,3 +368,18 @@ func (d EchoDeployments) Restart() error {
 	}
 	return nil
 }
+
+// RestartWithoutDrops restarts the given target while the first PodA instance sends it background traffic on the
+// given port, and returns an error if any request was dropped during the restart.
+func (d EchoDeployments) RestartWithoutDrops(t test.Failer, target echo.Instance, portName string) error {
+	return traffic.NewGenerator(t, traffic.Config{
+		Source: d.PodA[0],
+		Options: echo.CallOptions{
+			Target:   target,
+			PortName: portName,
+			// A retried request would hide a dropped connection.
+			Retry: echo.Retry{NoRetry: true},
+		},
+		Interval: restartTrafficInterval,
+	}).NoDroppedDuring(target.Restart)
+}
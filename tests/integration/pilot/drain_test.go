@@ -0,0 +1,75 @@
+//go:build integ
+// +build integ
+
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilot
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/test/framework"
+	"istio.io/istio/pkg/test/framework/components/echo"
+	"istio.io/istio/pkg/test/framework/components/echo/common"
+	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
+	"istio.io/istio/pkg/test/framework/components/namespace"
+)
+
+// TestNodeDrain verifies that traffic to a workload recovers once the workload is evicted from a drained node
+// and rescheduled onto another one.
+func TestNodeDrain(t *testing.T) {
+	framework.NewTest(t).
+		Features("traffic.routing").
+		Run(func(t framework.TestContext) {
+			c := t.Clusters().Default()
+			nodes, err := c.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			schedulable := 0
+			for _, n := range nodes.Items {
+				if !n.Spec.Unschedulable {
+					schedulable++
+				}
+			}
+			if schedulable < 2 {
+				t.Skip("draining a node requires at least 2 schedulable nodes")
+			}
+
+			ns := namespace.NewOrFail(t, t, namespace.Config{
+				Prefix: "node-drain",
+				Inject: true,
+			})
+			var server echo.Instance
+			echoboot.NewBuilder(t, c).
+				With(&server, echo.Config{
+					Service:   "drained",
+					Namespace: ns,
+					Ports:     common.EchoPorts,
+					Subsets:   []echo.SubsetConfig{{}},
+				}).
+				BuildOrFail(t)
+
+			if err := echo.VerifyNodeDrain(apps.PodA.GetOrFail(t, echo.InCluster(c)), server, echo.CallOptions{
+				Target:   server,
+				PortName: "http",
+			}); err != nil {
+				t.Fatal(err)
+			}
+		})
+}
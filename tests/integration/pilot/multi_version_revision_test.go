@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"text/tabwriter"
 	"time"
 
 	"istio.io/istio/pkg/config/protocol"
@@ -48,6 +49,7 @@ const (
 var versions = []string{NMinusOne, NMinusTwo, NMinusThree, NMinusFour, NMinusFive}
 
 type revisionedNamespace struct {
+	version   string
 	revision  string
 	namespace namespace.Instance
 }
@@ -88,6 +90,7 @@ func TestMultiVersionRevision(t *testing.T) {
 					t.Fatalf("failed to created revisioned namespace: %v", err)
 				}
 				revisionedNamespaces = append(revisionedNamespaces, revisionedNamespace{
+					version:   v,
 					revision:  rev,
 					namespace: ns,
 				})
@@ -121,6 +124,26 @@ func TestMultiVersionRevision(t *testing.T) {
 				})
 			}
 			instances := builder.BuildOrFail(t)
+			// verify each instance was injected by the control plane of its namespace's revision,
+			// and runs the proxy version of that revision
+			for _, ns := range revisionedNamespaces {
+				svc := fmt.Sprintf("revision-%s", ns.revision)
+				inst := instances.Match(echo.Service(svc))[0]
+				rev, err := echo.InjectedRevision(inst)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if rev != ns.revision {
+					t.Fatalf("expected %s to be injected by revision %s, got %s", svc, ns.revision, rev)
+				}
+				version, err := echo.ProxyVersion(inst)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if version != ns.version {
+					t.Fatalf("expected %s to run proxy version %s, got %s", svc, ns.version, version)
+				}
+			}
 			// add an existing pod from apps to the rotation to avoid an extra deployment
 			instances = append(instances, apps.PodA[0])
 
@@ -128,10 +151,73 @@ func TestMultiVersionRevision(t *testing.T) {
 		})
 }
 
+// TestUpgradeTo tests that traffic keeps flowing while the workloads of a namespace are moved to a newly
+// installed revision.
+func TestUpgradeTo(t *testing.T) {
+	framework.NewTest(t).
+		RequiresSingleCluster().
+		RequiresLocalControlPlane().
+		Features("installation.upgrade").
+		Run(func(t framework.TestContext) {
+			ns := namespace.NewOrFail(t, t, namespace.Config{
+				Prefix: "upgrade",
+				Inject: true,
+			})
+			var client, server echo.Instance
+			echoboot.NewBuilder(t).
+				With(&client, echo.Config{
+					Service:   "upgrade-client",
+					Namespace: ns,
+				}).
+				With(&server, echo.Config{
+					Service:   "upgrade-server",
+					Namespace: ns,
+					Ports: []echo.Port{
+						{
+							Name:         "http",
+							Protocol:     protocol.HTTP,
+							InstancePort: 8000,
+						},
+					},
+				}).
+				BuildOrFail(t)
+			before, err := echo.InjectedRevision(server)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// An empty version installs the revision from the current source tree.
+			if err := i.UpgradeTo(t, "", echo.Checker{
+				From: client,
+				Options: echo.CallOptions{
+					Target:   server,
+					PortName: "http",
+					Check:    check.OK(),
+				},
+			}, 0.95); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, inst := range []echo.Instance{client, server} {
+				after, err := echo.InjectedRevision(inst)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if after == before {
+					t.Fatalf("expected %s to be injected by the new revision, still injected by %s", inst.Config().Service, before)
+				}
+			}
+		})
+}
+
 // testAllEchoCalls takes list of revisioned namespaces and generates list of echo calls covering
-// communication between every pair of namespaces
+// communication between every pair of namespaces. The results are logged as a compatibility matrix.
 func testAllEchoCalls(t framework.TestContext, echoInstances []echo.Instance) {
 	trafficTypes := []string{"http", "tcp", "grpc"}
+	matrix := newCompatibilityMatrix(echoInstances, trafficTypes)
+	defer func() {
+		t.Logf("version compatibility matrix (source -> destination):\n%s", matrix)
+	}()
 	for _, source := range echoInstances {
 		for _, dest := range echoInstances {
 			if source == dest {
@@ -140,7 +226,7 @@ func testAllEchoCalls(t framework.TestContext, echoInstances []echo.Instance) {
 			for _, trafficType := range trafficTypes {
 				t.NewSubTest(fmt.Sprintf("%s-%s->%s", trafficType, source.Config().Service, dest.Config().Service)).
 					Run(func(t framework.TestContext) {
-						retry.UntilSuccessOrFail(t, func() error {
+						err := retry.UntilSuccess(func() error {
 							resp, err := source.Call(echo.CallOptions{
 								Target:   dest,
 								PortName: trafficType,
@@ -152,12 +238,78 @@ func testAllEchoCalls(t framework.TestContext, echoInstances []echo.Instance) {
 								check.NoError(),
 								check.OK()).Check(resp, err)
 						}, retry.Delay(time.Millisecond*150))
+						matrix.record(source, dest, trafficType, err == nil)
+						if err != nil {
+							t.Fatal(err)
+						}
 					})
 			}
 		}
 	}
 }
 
+// compatibilityMatrix records whether traffic of each type succeeded between each pair of echo instances.
+type compatibilityMatrix struct {
+	services     []string
+	trafficTypes []string
+	// results is keyed by source service, destination service, and traffic type.
+	results map[string]map[string]map[string]bool
+}
+
+func newCompatibilityMatrix(echoInstances []echo.Instance, trafficTypes []string) *compatibilityMatrix {
+	m := &compatibilityMatrix{
+		trafficTypes: trafficTypes,
+		results:      map[string]map[string]map[string]bool{},
+	}
+	for _, inst := range echoInstances {
+		m.services = append(m.services, inst.Config().Service)
+	}
+	return m
+}
+
+func (m *compatibilityMatrix) record(source, dest echo.Instance, trafficType string, success bool) {
+	src, dst := source.Config().Service, dest.Config().Service
+	if m.results[src] == nil {
+		m.results[src] = map[string]map[string]bool{}
+	}
+	if m.results[src][dst] == nil {
+		m.results[src][dst] = map[string]bool{}
+	}
+	m.results[src][dst][trafficType] = success
+}
+
+// String renders the matrix as a table, with a row per source and a column per destination. Each cell
+// lists the traffic types that failed, or "ok" if all succeeded.
+func (m *compatibilityMatrix) String() string {
+	sb := &strings.Builder{}
+	w := tabwriter.NewWriter(sb, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "\t%s\n", strings.Join(m.services, "\t"))
+	for _, src := range m.services {
+		cells := []string{src}
+		for _, dst := range m.services {
+			results, ok := m.results[src][dst]
+			if !ok {
+				cells = append(cells, "-")
+				continue
+			}
+			var failed []string
+			for _, trafficType := range m.trafficTypes {
+				if success, ran := results[trafficType]; ran && !success {
+					failed = append(failed, trafficType)
+				}
+			}
+			if len(failed) == 0 {
+				cells = append(cells, "ok")
+			} else {
+				cells = append(cells, "FAIL("+strings.Join(failed, ",")+")")
+			}
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
 // installRevisionOrFail takes an Istio version and installs a revisioned control plane running that version
 // provided istio version must be present in tests/integration/pilot/testdata/upgrade for the installation to succeed
 func installRevisionOrFail(t framework.TestContext, version string, configs map[string]string) {
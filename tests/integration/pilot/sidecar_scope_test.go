@@ -0,0 +1,66 @@
+//go:build integ
+// +build integ
+
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilot
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/framework"
+	"istio.io/istio/pkg/test/framework/components/echo"
+	"istio.io/istio/pkg/test/framework/components/echo/common"
+	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
+	"istio.io/istio/pkg/test/framework/components/namespace"
+)
+
+// TestSidecarScopeReducesConfig verifies that a Sidecar restricting egress to the workload's own namespace
+// drops the clusters of services in other namespaces from the proxy configuration.
+func TestSidecarScopeReducesConfig(t *testing.T) {
+	framework.NewTest(t).
+		Features("traffic.routing").
+		Run(func(t framework.TestContext) {
+			ns := namespace.NewOrFail(t, t, namespace.Config{
+				Prefix: "sidecar-scope",
+				Inject: true,
+			})
+			var client echo.Instance
+			echoboot.NewBuilder(t, t.Clusters().Default()).
+				With(&client, echo.Config{
+					Service:   "client",
+					Namespace: ns,
+					Ports:     common.EchoPorts,
+					Subsets:   []echo.SubsetConfig{{}},
+				}).
+				BuildOrFail(t)
+
+			if err := echo.VerifyConfigReduced(client, func() error {
+				return t.ConfigIstio().YAML(`
+apiVersion: networking.istio.io/v1alpha3
+kind: Sidecar
+metadata:
+  name: restrict-to-namespace
+spec:
+  egress:
+  - hosts:
+    - "./*"
+    - "istio-system/*"
+`).Apply(ns.Name())
+			}); err != nil {
+				t.Fatal(err)
+			}
+		})
+}
@@ -46,7 +46,7 @@ import (
 )
 
 func newRootNS(ctx framework.TestContext) namespace.Instance {
-	return istio.ClaimSystemNamespaceOrFail(ctx, ctx)
+	return ist.SystemNamespace(ctx)
 }
 
 // TestAuthorization_mTLS tests v1beta1 authorization with mTLS.
@@ -27,9 +27,11 @@ import (
 	"testing"
 
 	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/echo/common"
 	"istio.io/istio/pkg/test/env"
 	"istio.io/istio/pkg/test/framework"
+	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
 	"istio.io/istio/pkg/test/framework/components/istio"
@@ -58,6 +60,13 @@ type EchoDeployments struct {
 	Naked, Server echo.Instances
 }
 
+// NakedClientOrFail returns the Naked (out-of-mesh) echo instance in the given cluster, to be used as the
+// source of calls originating outside the mesh.
+func (d EchoDeployments) NakedClientOrFail(t test.Failer, c cluster.Cluster) echo.Instance {
+	t.Helper()
+	return d.Naked.GetOrFail(t, echo.InCluster(c))
+}
+
 var (
 	inst istio.Instance
 	apps = &EchoDeployments{}
@@ -31,7 +31,6 @@ import (
 	"istio.io/istio/pkg/test/echo/common/scheme"
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/echo"
-	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/pkg/test/framework/components/namespace"
 	"istio.io/istio/pkg/test/util/retry"
 	"istio.io/istio/tests/integration/security/util"
@@ -109,9 +108,8 @@ func TestSecureNaming(t *testing.T) {
 			if t.AllClusters().IsMulticluster() {
 				t.Skip("https://github.com/istio/istio/issues/37307")
 			}
-			istioCfg := istio.DefaultConfigOrFail(t, t)
 			testNamespace := apps.Namespace
-			namespace.ClaimOrFail(t, t, istioCfg.SystemNamespace)
+			inst.SystemNamespace(t)
 			// Check that the CA certificate in the configmap of each namespace is as expected, which
 			// is used for data plane to control plane TLS authentication.
 			retry.UntilSuccessOrFail(t, func() error {
@@ -32,6 +32,7 @@ import (
 	epb "istio.io/istio/pkg/test/echo/proto"
 	"istio.io/istio/pkg/test/env"
 	"istio.io/istio/pkg/test/framework"
+	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/util/retry"
 )
@@ -92,103 +93,99 @@ spec:
 // - works for both HTTP and TCP protocol
 // - works for pass through filter chains
 func TestTrustDomainValidation(t *testing.T) {
-	framework.NewTest(t).Features("security.peer.trust-domain-validation").Run(
-		func(ctx framework.TestContext) {
-			if ctx.AllClusters().IsMulticluster() {
-				ctx.Skip("https://github.com/istio/istio/issues/37307")
+	framework.NewTest(t).Features("security.peer.trust-domain-validation").PerCluster(
+		func(t framework.TestContext, c cluster.Cluster) {
+			if t.AllClusters().IsMulticluster() {
+				t.Skip("https://github.com/istio/istio/issues/37307")
 			}
 
 			testNS := apps.Namespace
 
-			ctx.ConfigIstio().YAML(fmt.Sprintf(policy, testNS.Name())).ApplyOrFail(ctx, testNS.Name())
+			t.ConfigIstio().YAML(fmt.Sprintf(policy, testNS.Name())).ApplyOrFail(t, testNS.Name())
 
 			trustDomains := map[string]struct {
 				cert string
 				key  string
 			}{
 				"foo": {
-					cert: readFile(ctx, "workload-foo-cert.pem"),
-					key:  readFile(ctx, "workload-foo-key.pem"),
+					cert: readFile(t, "workload-foo-cert.pem"),
+					key:  readFile(t, "workload-foo-key.pem"),
 				},
 				"bar": {
-					cert: readFile(ctx, "workload-bar-cert.pem"),
-					key:  readFile(ctx, "workload-bar-key.pem"),
+					cert: readFile(t, "workload-bar-cert.pem"),
+					key:  readFile(t, "workload-bar-key.pem"),
 				},
 			}
 
-			for _, cluster := range ctx.Clusters() {
-				ctx.NewSubTest(fmt.Sprintf("From %s", cluster.StableName())).Run(func(t framework.TestContext) {
-					// naked: only test app without sidecar, send requests from trust domain aliases
-					// client: app with sidecar, send request from cluster.local
-					// server: app with sidecar, verify requests from cluster.local or trust domain aliases
-					client := apps.Client.GetOrFail(t, echo.InCluster(cluster))
-					naked := apps.Naked.GetOrFail(t, echo.InCluster(cluster))
-					server := apps.Server.GetOrFail(t, echo.InCluster(cluster))
-					verify := func(ctx framework.TestContext, from echo.Instance, td, port string, s scheme.Instance, allow bool) {
-						ctx.Helper()
-						want := "allow"
-						if !allow {
-							want = "deny"
-						}
-						name := fmt.Sprintf("%s[%s]->server:%s[%s]", from.Config().Service, td, port, want)
-						ctx.NewSubTest(name).Run(func(t framework.TestContext) {
-							t.Helper()
-							opt := echo.CallOptions{
-								Target:   server,
-								PortName: port,
-								Address:  "server",
-								Scheme:   s,
-								TLS: echo.TLS{
-									Cert: trustDomains[td].cert,
-									Key:  trustDomains[td].key,
-								},
-								Retry: echo.Retry{
-									NoRetry: true,
-								},
-							}
-							retry.UntilSuccessOrFail(t, func() error {
-								var resp echoClient.Responses
-								var err error
-								if port == passThrough {
-									// Manually make the request for pass through port.
-									resp, err = workload(t, from).ForwardEcho(context.TODO(), &epb.ForwardEchoRequest{
-										Url:   fmt.Sprintf("tcp://%s", net.JoinHostPort(workload(t, server).Address(), "9000")),
-										Count: 1,
-										Cert:  trustDomains[td].cert,
-										Key:   trustDomains[td].key,
-									})
-								} else {
-									resp, err = from.Call(opt)
-								}
-								if allow {
-									return check.OK().Check(resp, err)
-								}
-								return check.ErrorContains("tls: unknown certificate").Check(resp, err)
-							}, retry.Delay(250*time.Millisecond), retry.Timeout(30*time.Second), retry.Converge(5))
-						})
+			// naked: only test app without sidecar, send requests from trust domain aliases
+			// client: app with sidecar, send request from cluster.local
+			// server: app with sidecar, verify requests from cluster.local or trust domain aliases
+			client := apps.Client.ForClusterOrFail(t, c)
+			naked := apps.NakedClientOrFail(t, c)
+			server := apps.Server.ForClusterOrFail(t, c)
+			verify := func(ctx framework.TestContext, from echo.Instance, td, port string, s scheme.Instance, allow bool) {
+				ctx.Helper()
+				want := "allow"
+				if !allow {
+					want = "deny"
+				}
+				name := fmt.Sprintf("%s[%s]->server:%s[%s]", from.Config().Service, td, port, want)
+				ctx.NewSubTest(name).Run(func(t framework.TestContext) {
+					t.Helper()
+					opt := echo.CallOptions{
+						Target:   server,
+						PortName: port,
+						Address:  "server",
+						Scheme:   s,
+						TLS: echo.TLS{
+							Cert: trustDomains[td].cert,
+							Key:  trustDomains[td].key,
+						},
+						Retry: echo.Retry{
+							NoRetry: true,
+						},
 					}
-
-					// Request using plaintext should always allowed.
-					verify(t, client, "plaintext", httpPlaintext, scheme.HTTP, true)
-					verify(t, client, "plaintext", tcpPlaintext, scheme.TCP, true)
-					verify(t, naked, "plaintext", httpPlaintext, scheme.HTTP, true)
-					verify(t, naked, "plaintext", tcpPlaintext, scheme.TCP, true)
-
-					// Request from local trust domain should always allowed.
-					verify(t, client, "cluster.local", httpMTLS, scheme.HTTP, true)
-					verify(t, client, "cluster.local", tcpMTLS, scheme.TCP, true)
-
-					// Trust domain foo is added as trust domain alias.
-					// Request from trust domain bar should be denied.
-					// Request from trust domain foo should be allowed.
-					verify(t, naked, "bar", httpMTLS, scheme.HTTPS, false)
-					verify(t, naked, "bar", tcpMTLS, scheme.TCP, false)
-					verify(t, naked, "bar", passThrough, scheme.TCP, false)
-					verify(t, naked, "foo", httpMTLS, scheme.HTTPS, true)
-					verify(t, naked, "foo", tcpMTLS, scheme.TCP, true)
-					verify(t, naked, "foo", passThrough, scheme.TCP, true)
+					retry.UntilSuccessOrFail(t, func() error {
+						var resp echoClient.Responses
+						var err error
+						if port == passThrough {
+							// Manually make the request for pass through port.
+							resp, err = workload(t, from).ForwardEcho(context.TODO(), &epb.ForwardEchoRequest{
+								Url:   fmt.Sprintf("tcp://%s", net.JoinHostPort(workload(t, server).Address(), "9000")),
+								Count: 1,
+								Cert:  trustDomains[td].cert,
+								Key:   trustDomains[td].key,
+							})
+						} else {
+							resp, err = from.Call(opt)
+						}
+						if allow {
+							return check.OK().Check(resp, err)
+						}
+						return check.ErrorContains("tls: unknown certificate").Check(resp, err)
+					}, retry.Delay(250*time.Millisecond), retry.Timeout(30*time.Second), retry.Converge(5))
 				})
 			}
+
+			// Request using plaintext should always allowed.
+			verify(t, client, "plaintext", httpPlaintext, scheme.HTTP, true)
+			verify(t, client, "plaintext", tcpPlaintext, scheme.TCP, true)
+			verify(t, naked, "plaintext", httpPlaintext, scheme.HTTP, true)
+			verify(t, naked, "plaintext", tcpPlaintext, scheme.TCP, true)
+
+			// Request from local trust domain should always allowed.
+			verify(t, client, "cluster.local", httpMTLS, scheme.HTTP, true)
+			verify(t, client, "cluster.local", tcpMTLS, scheme.TCP, true)
+
+			// Trust domain foo is added as trust domain alias.
+			// Request from trust domain bar should be denied.
+			// Request from trust domain foo should be allowed.
+			verify(t, naked, "bar", httpMTLS, scheme.HTTPS, false)
+			verify(t, naked, "bar", tcpMTLS, scheme.TCP, false)
+			verify(t, naked, "bar", passThrough, scheme.TCP, false)
+			verify(t, naked, "foo", httpMTLS, scheme.HTTPS, true)
+			verify(t, naked, "foo", tcpMTLS, scheme.TCP, true)
+			verify(t, naked, "foo", passThrough, scheme.TCP, true)
 		})
 }
 
@@ -18,15 +18,13 @@
 package externalca
 
 import (
-	"fmt"
 	"testing"
 
 	"istio.io/istio/pkg/test/echo/check"
 	"istio.io/istio/pkg/test/echo/common/scheme"
 	"istio.io/istio/pkg/test/framework"
+	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/components/echo"
-	"istio.io/istio/pkg/test/framework/components/istio"
-	"istio.io/istio/pkg/test/framework/components/namespace"
 	"istio.io/istio/tests/integration/security/util"
 	"istio.io/istio/tests/integration/security/util/scheck"
 )
@@ -36,7 +34,7 @@ import (
 func TestReachability(t *testing.T) {
 	framework.NewTest(t).
 		Features("security.externalca.reachability").
-		Run(func(t framework.TestContext) {
+		PerCluster(func(t framework.TestContext, c cluster.Cluster) {
 			/* Test cases cannot be run in multi-cluster environments when using per cluster K8s CA Signers. Revisit this when
 			 * (a) Test environment can be modified to deploy external-signer common to all clusters in multi-cluster environment OR
 			 * (b) When trust-bundle for workload ISTIO_MUTUAL mtls can be explicitly configured PER Istio Trust Domain
@@ -44,32 +42,27 @@ func TestReachability(t *testing.T) {
 			if t.Clusters().IsMulticluster() {
 				t.Skip()
 			}
-			istioCfg := istio.DefaultConfigOrFail(t, t)
 			testNamespace := apps.Namespace
-			namespace.ClaimOrFail(t, t, istioCfg.SystemNamespace)
+			inst.SystemNamespace(t)
 			callCount := 1
 			if t.Clusters().IsMulticluster() {
 				// so we can validate all clusters are hit
 				callCount = util.CallsPerCluster * len(t.Clusters())
 			}
 			bSet := apps.B.Match(echo.Namespace(testNamespace.Name()))
-			for _, cluster := range t.Clusters() {
-				t.NewSubTest(fmt.Sprintf("From %s", cluster.StableName())).Run(func(t framework.TestContext) {
-					a := apps.A.Match(echo.InCluster(cluster)).Match(echo.Namespace(testNamespace.Name()))[0]
-					t.NewSubTest("Basic reachability with external ca").
-						Run(func(t framework.TestContext) {
-							// Verify mTLS works between a and b
-							opts := echo.CallOptions{
-								Target:   bSet[0],
-								PortName: "http",
-								Scheme:   scheme.HTTP,
-								Count:    callCount,
-							}
-							opts.Check = check.And(check.OK(), scheck.ReachedClusters(bSet, &opts))
+			a := apps.A.Match(echo.InCluster(c)).Match(echo.Namespace(testNamespace.Name()))[0]
+			t.NewSubTest("Basic reachability with external ca").
+				Run(func(t framework.TestContext) {
+					// Verify mTLS works between a and b
+					opts := echo.CallOptions{
+						Target:   bSet[0],
+						PortName: "http",
+						Scheme:   scheme.HTTP,
+						Count:    callCount,
+					}
+					opts.Check = check.And(check.OK(), scheck.ReachedClusters(bSet, &opts))
 
-							a.CallOrFail(t, opts)
-						})
+					a.CallOrFail(t, opts)
 				})
-			}
 		})
 }
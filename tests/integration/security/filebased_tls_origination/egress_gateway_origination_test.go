@@ -39,7 +39,6 @@ import (
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
-	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/pkg/test/framework/components/namespace"
 	"istio.io/istio/pkg/test/framework/resource"
 	"istio.io/istio/pkg/test/util/retry"
@@ -123,8 +122,7 @@ func TestEgressGatewayTls(t *testing.T) {
 					Run(func(t framework.TestContext) {
 						bufDestinationRule := createDestinationRule(t, serviceNamespace, tc.destinationRuleMode, tc.fakeRootCert)
 
-						istioCfg := istio.DefaultConfigOrFail(t, t)
-						systemNamespace := namespace.ClaimOrFail(t, t, istioCfg.SystemNamespace)
+						systemNamespace := inst.SystemNamespace(t)
 
 						t.ConfigIstio().YAML(bufDestinationRule.String()).ApplyOrFail(t, systemNamespace.Name())
 
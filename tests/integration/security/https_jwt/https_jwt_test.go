@@ -29,7 +29,6 @@ import (
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/echotest"
-	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/pkg/test/framework/resource"
 	"istio.io/istio/pkg/test/kube"
 	"istio.io/istio/tests/common/jwt"
@@ -49,7 +48,7 @@ func TestJWTHTTPS(t *testing.T) {
 			}
 
 			ns := apps.Namespace1
-			istioSystemNS := istio.ClaimSystemNamespaceOrFail(t, t)
+			istioSystemNS := ist.SystemNamespace(t)
 
 			t.ConfigKube().EvalFile(map[string]string{
 				"Namespace": istioSystemNS.Name(),
@@ -22,7 +22,6 @@ import (
 
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/echo"
-	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/tests/integration/security/util/reachability"
 )
 
@@ -37,7 +36,7 @@ func TestMtlsStrictK8sCA(t *testing.T) {
 	framework.NewTest(t).
 		Features("security.control-plane.k8s-certs.jwt").
 		Run(func(t framework.TestContext) {
-			systemNM := istio.ClaimSystemNamespaceOrFail(t, t)
+			systemNM := inst.SystemNamespace(t)
 			testCases := []reachability.TestCase{
 				{
 					ConfigFile: "global-mtls-on-no-dr.yaml",
@@ -19,14 +19,10 @@ package security
 
 import (
 	"fmt"
-	"net/http"
 	"testing"
 
 	"istio.io/istio/pkg/config/protocol"
-	"istio.io/istio/pkg/http/headers"
 	"istio.io/istio/pkg/test"
-	echoClient "istio.io/istio/pkg/test/echo"
-	"istio.io/istio/pkg/test/echo/check"
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/echotest"
@@ -642,41 +638,12 @@ spec:
 									want = expect.plaintextSucceeds
 								}
 								name := fmt.Sprintf("%v/port %d[%t]", nameSuffix, expect.port.ServicePort, want)
-								host := fmt.Sprintf("%s:%d", getWorkload(dest[0], t).Address(), expect.port.ServicePort)
-								callOpt := echo.CallOptions{
-									Count: util.CallsPerCluster * len(dest),
-									Port:  expect.port,
-									HTTP: echo.HTTP{
-										Headers: headers.New().WithHost(host).Build(),
-									},
-									Message: "HelloWorld",
-									// Do not set Target to dest, otherwise fillInCallOptions() will
-									// complain with port does not match.
-									Address: getWorkload(dest[0], t).Address(),
-									Check: func(responses echoClient.Responses, err error) error {
-										if want {
-											if err != nil {
-												return fmt.Errorf("want allow but got error: %v", err)
-											}
-											if responses.Len() < 1 {
-												return fmt.Errorf("received no responses from request to %s", host)
-											}
-											if okErr := check.OK().Check(responses, err); okErr != nil && expect.port.Protocol == protocol.HTTP {
-												return fmt.Errorf("want status %d but got %s", http.StatusOK, okErr.Error())
-											}
-										} else {
-											// Check HTTP forbidden response
-											if responses.Len() >= 1 && check.Status(http.StatusForbidden).Check(responses, err) == nil {
-												return nil
-											}
-
-											if err == nil {
-												return fmt.Errorf("want error but got none: %v", responses.String())
-											}
-										}
-										return nil
-									},
+								callOpt, err := echo.PassThroughCallOptions(dest[0], *expect.port, want)
+								if err != nil {
+									t.Fatal(err)
 								}
+								callOpt.Count = util.CallsPerCluster * len(dest)
+								callOpt.Message = "HelloWorld"
 								t.NewSubTest(name).Run(func(t framework.TestContext) {
 									src.CallOrFail(t, callOpt)
 								})
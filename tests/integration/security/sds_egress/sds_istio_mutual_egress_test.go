@@ -28,7 +28,6 @@ import (
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
-	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/pkg/test/framework/components/namespace"
 	"istio.io/istio/pkg/test/framework/components/prometheus"
 	"istio.io/istio/pkg/test/util/retry"
@@ -55,9 +54,7 @@ func TestSdsEgressGatewayIstioMutual(t *testing.T) {
 	framework.NewTest(t).
 		Features("security.egress.mtls.sds").
 		Run(func(t framework.TestContext) {
-			istioCfg := istio.DefaultConfigOrFail(t, t)
-
-			namespace.ClaimOrFail(t, t, istioCfg.SystemNamespace)
+			inst.SystemNamespace(t)
 			ns := namespace.NewOrFail(t, t, namespace.Config{
 				Prefix: "sds-egress-gateway-workload",
 				Inject: true,
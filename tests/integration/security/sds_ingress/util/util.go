@@ -122,8 +122,7 @@ func CreateIngressKubeSecret(t framework.TestContext, credName string,
 	t.Helper()
 
 	// Get namespace for ingress gateway pod.
-	istioCfg := istio.DefaultConfigOrFail(t, t)
-	systemNS := namespace.ClaimOrFail(t, t, istioCfg.SystemNamespace)
+	systemNS := istio.GetOrFail(t, t).SystemNamespace(t)
 	CreateIngressKubeSecretInNamespace(t, credName, ingressType, ingressCred, isCompoundAndNotGeneric, systemNS.Name(), clusters...)
 }
 
@@ -175,8 +174,7 @@ func CreateIngressKubeSecretInNamespace(t framework.TestContext, credName string
 // nolint: interfacer
 func deleteKubeSecret(ctx framework.TestContext, credName string) {
 	// Get namespace for ingress gateway pod.
-	istioCfg := istio.DefaultConfigOrFail(ctx, ctx)
-	systemNS := namespace.ClaimOrFail(ctx, ctx, istioCfg.SystemNamespace)
+	systemNS := istio.GetOrFail(ctx, ctx).SystemNamespace(ctx)
 
 	// Create Kubernetes secret for ingress gateway
 	cluster := ctx.Clusters().Default()
@@ -340,7 +338,7 @@ func RotateSecrets(ctx framework.TestContext, credName string, // nolint:interfa
 	ctx.Helper()
 	cluster := ctx.Clusters().Default()
 	ist := istio.GetOrFail(ctx, ctx)
-	systemNS := namespace.ClaimOrFail(ctx, ctx, ist.Settings().SystemNamespace)
+	systemNS := ist.SystemNamespace(ctx)
 	scrt, err := cluster.CoreV1().Secrets(systemNS.Name()).Get(context.TODO(), credName, metav1.GetOptions{})
 	if err != nil {
 		ctx.Errorf("Failed to get secret %s:%s (error: %s)", systemNS.Name(), credName, err)
@@ -205,10 +205,10 @@ func SetupApps(ctx resource.Context, i istio.Instance, apps *EchoDeployments, bu
 	builder := echoboot.NewBuilder(ctx).
 		WithClusters(ctx.Clusters()...).
 		WithConfig(EchoConfig(ASvc, apps.Namespace1, false, nil)).
-		WithConfig(EchoConfig(BSvc, apps.Namespace1, false, nil)).
-		WithConfig(EchoConfig(CSvc, apps.Namespace1, false, nil)).
+		WithConfigInNamespaces(EchoConfig(BSvc, nil, false, nil), apps.Namespace1, apps.Namespace2).
+		WithConfigInNamespaces(EchoConfig(CSvc, nil, false, nil), apps.Namespace1, apps.Namespace2, apps.Namespace3).
 		WithConfig(EchoConfig(DSvc, apps.Namespace1, false, nil)).
-		WithConfig(EchoConfig(ESvc, apps.Namespace1, false, nil)).
+		WithConfigInNamespaces(EchoConfig(ESvc, nil, false, nil), apps.Namespace1, apps.Namespace2).
 		WithConfig(func() echo.Config {
 			// Multi-version specific setup
 			multiVersionCfg := EchoConfig(MultiversionSvc, apps.Namespace1, false, nil)
@@ -227,10 +227,6 @@ func SetupApps(ctx resource.Context, i istio.Instance, apps *EchoDeployments, bu
 		}()).
 		WithConfig(EchoConfig(NakedSvc, apps.Namespace1, false, echo.NewAnnotations().
 			SetBool(echo.SidecarInject, false))).
-		WithConfig(EchoConfig(BSvc, apps.Namespace2, false, nil)).
-		WithConfig(EchoConfig(CSvc, apps.Namespace2, false, nil)).
-		WithConfig(EchoConfig(ESvc, apps.Namespace2, false, nil)).
-		WithConfig(EchoConfig(CSvc, apps.Namespace3, false, nil)).
 		WithConfig(func() echo.Config {
 			// VM specific setup
 			vmCfg := EchoConfig(VMSvc, apps.Namespace1, false, nil)
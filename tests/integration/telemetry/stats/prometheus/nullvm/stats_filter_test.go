@@ -18,13 +18,20 @@
 package nullvm
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/pkg/test/framework/features"
 	"istio.io/istio/pkg/test/framework/label"
 	"istio.io/istio/pkg/test/framework/resource"
+	"istio.io/istio/pkg/test/util/retry"
+	"istio.io/istio/pkg/util/gogoprotomarshal"
 	common "istio.io/istio/tests/integration/telemetry/stats/prometheus"
 )
 
@@ -36,6 +43,159 @@ func TestStatsFilter(t *testing.T) {
 	common.TestStatsFilter(t, features.Feature("observability.telemetry.stats.prometheus.http.nullvm"))
 }
 
+// TestProtocolSniffingMetrics verifies that the protocol sniffed on auto-detected ports is reported in the
+// request_protocol label of the metrics.
+func TestProtocolSniffingMetrics(t *testing.T) {
+	framework.NewTest(t).
+		Features("observability.telemetry.stats.prometheus.http.nullvm").
+		Run(func(t framework.TestContext) {
+			for _, cltInstance := range common.GetClientInstances() {
+				cltInstance := cltInstance
+				t.NewSubTest(cltInstance.Config().Cluster.StableName()).Run(func(t framework.TestContext) {
+					common.ValidateSniffedProtocol(t, cltInstance, "sniffed-http", "http")
+					common.ValidateSniffedProtocol(t, cltInstance, "sniffed-tcp", "tcp")
+				})
+			}
+		})
+}
+
+// TestStatsGRPC verifies that gRPC requests are reported with their gRPC status and message counts.
+func TestStatsGRPC(t *testing.T) {
+	framework.NewTest(t).
+		Features("observability.telemetry.stats.prometheus.http.nullvm").
+		Run(func(t framework.TestContext) {
+			for _, cltInstance := range common.GetClientInstances() {
+				cltInstance := cltInstance
+				t.NewSubTest(cltInstance.Config().Cluster.StableName()).Run(func(t framework.TestContext) {
+					common.ValidateGRPCMetrics(t, cltInstance)
+				})
+			}
+		})
+}
+
+// TestStatsRequestCount verifies that istio_requests_total counts exactly the requests that were sent.
+func TestStatsRequestCount(t *testing.T) {
+	framework.NewTest(t).
+		Features("observability.telemetry.stats.prometheus.http.nullvm").
+		Run(func(t framework.TestContext) {
+			for _, cltInstance := range common.GetClientInstances() {
+				cltInstance := cltInstance
+				t.NewSubTest(cltInstance.Config().Cluster.StableName()).Run(func(t framework.TestContext) {
+					common.ValidateRequestCount(t, cltInstance)
+				})
+			}
+		})
+}
+
+// TestStatsCustomDimension verifies that dimensions added through the Telemetry API are reported.
+func TestStatsCustomDimension(t *testing.T) {
+	framework.NewTest(t).
+		Features("observability.telemetry.stats.prometheus.http.nullvm").
+		Run(func(t framework.TestContext) {
+			t.ConfigIstio().Telemetry("custom-dimension", map[string]string{
+				common.CustomDimension: "request.headers['x-custom-dimension']",
+			}).ApplyOrFail(t, common.GetAppNamespace().Name())
+			for _, cltInstance := range common.GetClientInstances() {
+				cltInstance := cltInstance
+				t.NewSubTest(cltInstance.Config().Cluster.StableName()).Run(func(t framework.TestContext) {
+					common.ValidateCustomDimension(t, cltInstance, "x-custom-dimension", "custom")
+				})
+			}
+		})
+}
+
+// TestStatsRemoveTags verifies that tags removed through the Telemetry API are no longer reported.
+func TestStatsRemoveTags(t *testing.T) {
+	framework.NewTest(t).
+		Features("observability.telemetry.stats.prometheus.http.nullvm").
+		Run(func(t framework.TestContext) {
+			t.ConfigIstio().RemoveMetricTags("REQUEST_COUNT", "request_protocol").ApplyOrFail(t, common.GetAppNamespace().Name())
+			for _, cltInstance := range common.GetClientInstances() {
+				cltInstance := cltInstance
+				t.NewSubTest(cltInstance.Config().Cluster.StableName()).Run(func(t framework.TestContext) {
+					common.ValidateTagRemoved(t, cltInstance, "request_protocol")
+				})
+			}
+		})
+}
+
+// TestStatsRuntimeParity verifies that the stats filter reports the same request metrics after it is moved
+// from the nullvm to the Wasm runtime.
+func TestStatsRuntimeParity(t *testing.T) {
+	framework.NewTest(t).
+		Features("observability.telemetry.stats.prometheus.http.nullvm").
+		Run(func(t framework.TestContext) {
+			common.CompareRuntimeMetrics(t, common.GetClientInstances()[0], switchStatsFilterToWasm, 0.1)
+		})
+}
+
+var wasmRuntimeReplacer = strings.NewReplacer(
+	`"runtime":"envoy.wasm.runtime.null"`, `"runtime":"envoy.wasm.runtime.v8"`,
+	`{"inline_string":"envoy.wasm.stats"}`, `{"filename":"/etc/istio/extensions/stats-filter.compiled.wasm"}`,
+)
+
+// switchStatsFilterToWasm rewrites the installed stats filters to load the stats plugin in the V8 runtime,
+// restoring the original filters when the test completes, and waits for the server sidecars to start it.
+func switchStatsFilterToWasm(t framework.TestContext) {
+	systemNamespace := (*common.GetIstioInstance()).Settings().SystemNamespace
+	for _, c := range t.Clusters().Configs() {
+		filters := c.Istio().NetworkingV1alpha3().EnvoyFilters(systemNamespace)
+		list, err := filters.List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range list.Items {
+			original := &list.Items[i]
+			if !strings.HasPrefix(original.Name, "stats-filter-") {
+				continue
+			}
+			js, err := gogoprotomarshal.ToJSON(&original.Spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated := original.DeepCopy()
+			updated.Spec.Reset()
+			if err := gogoprotomarshal.ApplyJSON(wasmRuntimeReplacer.Replace(js), &updated.Spec); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := filters.Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				latest, err := filters.Get(context.TODO(), original.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Logf("failed to restore EnvoyFilter %s: %v", original.Name, err)
+					return
+				}
+				original.ResourceVersion = latest.ResourceVersion
+				if _, err := filters.Update(context.TODO(), original, metav1.UpdateOptions{}); err != nil {
+					t.Logf("failed to restore EnvoyFilter %s: %v", original.Name, err)
+				}
+			})
+		}
+	}
+
+	for _, srv := range common.GetServerInstances() {
+		for _, w := range srv.WorkloadsOrFail(t) {
+			w := w
+			retry.UntilSuccessOrFail(t, func() error {
+				stats, err := w.Sidecar().Stats()
+				if err != nil {
+					return err
+				}
+				if mf := stats["envoy_wasm_envoy_wasm_runtime_v8_active"]; mf != nil {
+					for _, m := range mf.GetMetric() {
+						if m.GetGauge().GetValue() > 0 {
+							return nil
+						}
+					}
+				}
+				return fmt.Errorf("no active V8 Wasm VM on %s", w.PodName())
+			}, retry.Delay(framework.TelemetryRetryDelay), retry.Timeout(framework.TelemetryRetryTimeout))
+		}
+	}
+}
+
 func TestMain(m *testing.M) {
 	framework.NewSuite(m).
 		Label(label.CustomSetup).
@@ -20,9 +20,24 @@ package nullvm
 import (
 	"testing"
 
+	"istio.io/istio/pkg/test/framework"
 	common "istio.io/istio/tests/integration/telemetry/stats/prometheus"
 )
 
 func TestTcpMetric(t *testing.T) { // nolint:interfacer
 	common.TestStatsTCPFilter(t, "observability.telemetry.stats.prometheus.tcp")
 }
+
+// TestTCPBytes verifies that the TCP byte counters account for the payload sent over each connection.
+func TestTCPBytes(t *testing.T) {
+	framework.NewTest(t).
+		Features("observability.telemetry.stats.prometheus.tcp").
+		Run(func(t framework.TestContext) {
+			for _, cltInstance := range common.GetClientInstances() {
+				cltInstance := cltInstance
+				t.NewSubTest(cltInstance.Config().Cluster.StableName()).Run(func(t framework.TestContext) {
+					common.ValidateTCPBytes(t, cltInstance, 1024)
+				})
+			}
+		})
+}
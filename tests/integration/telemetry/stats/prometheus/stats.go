@@ -19,16 +19,20 @@ package prometheus
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/prometheus/common/model"
 	"golang.org/x/sync/errgroup"
 
 	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/http/headers"
 	"istio.io/istio/pkg/test/echo/check"
-	"istio.io/istio/pkg/test/echo/common"
-	"istio.io/istio/pkg/test/echo/common/scheme"
 	"istio.io/istio/pkg/test/framework"
+	"istio.io/istio/pkg/test/framework/components/cluster"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
 	"istio.io/istio/pkg/test/framework/components/istio"
@@ -44,13 +48,22 @@ import (
 var (
 	client, server    echo.Instances
 	nonInjectedServer echo.Instances
-	mockProm          echo.Instances
 	ist               istio.Instance
 	appNsInst         namespace.Instance
 	promInst          prometheus.Instance
 	ingr              []ingress.Instance
 )
 
+// serverHTTPPort is the port on which the "server" service receives HTTP traffic.
+const serverHTTPPort = 8090
+
+// echoMetricsPort is the port on which echo apps serve their own metrics.
+const echoMetricsPort = 15014
+
+// CustomDimension is a dimension that the server workloads are allowed to report in addition to the
+// standard ones.
+const CustomDimension = "custom_dimension"
+
 var PeerAuthenticationConfig = `
 apiVersion: security.istio.io/v1beta1
 kind: PeerAuthentication
@@ -95,23 +108,29 @@ func TestStatsFilter(t *testing.T, feature features.Feature) {
 	framework.NewTest(t).
 		Features(feature).
 		Run(func(t framework.TestContext) {
-			// Enable strict mTLS. This is needed for mock secured prometheus scraping test.
+			// Enable strict mTLS. This is needed for the secured prometheus scraping test.
 			t.ConfigIstio().YAML(PeerAuthenticationConfig).ApplyOrFail(t, ist.Settings().SystemNamespace)
 			g, _ := errgroup.WithContext(context.Background())
 			for _, cltInstance := range client {
 				cltInstance := cltInstance
 				g.Go(func() error {
+					if err := SendTraffic(cltInstance); err != nil {
+						return err
+					}
+					c := cltInstance.Config().Cluster
+					sourceCluster := "Kubernetes"
+					if len(t.AllClusters()) > 1 {
+						sourceCluster = c.Name()
+					}
+					sourceQuery, destinationQuery, appQuery := buildQuery(sourceCluster)
+					prom := GetPromInstance()
+					// Make sure the client and the servers reporting the queried metrics have been scraped since
+					// the traffic was sent.
+					scraped := append(echo.Instances{cltInstance}, server.Match(echo.InCluster(c))...)
+					if err := waitForScrape(c, scraped, 1); err != nil {
+						return err
+					}
 					err := retry.UntilSuccess(func() error {
-						if err := SendTraffic(cltInstance); err != nil {
-							return err
-						}
-						c := cltInstance.Config().Cluster
-						sourceCluster := "Kubernetes"
-						if len(t.AllClusters()) > 1 {
-							sourceCluster = c.Name()
-						}
-						sourceQuery, destinationQuery, appQuery := buildQuery(sourceCluster)
-						prom := GetPromInstance()
 						// Query client side metrics
 						if _, err := prom.QuerySum(c, sourceQuery); err != nil {
 							util.PromDiff(t, prom, c, sourceQuery)
@@ -128,7 +147,15 @@ func TestStatsFilter(t *testing.T, feature features.Feature) {
 							util.PromDiff(t, prom, c, destinationQuery)
 							return err
 						}
-						// This query will continue to increase due to readiness probe; don't wait for it to converge
+						// Requests may be served in any of the server clusters, so destination_cluster must name one of them.
+						destinationClusters := []string{"Kubernetes"}
+						if len(t.AllClusters()) > 1 {
+							destinationClusters = server.Clusters().Names()
+						}
+						if err := ValidateClusterLabels(c, destinationQuery, sourceCluster, destinationClusters); err != nil {
+							return err
+						}
+						// Readiness probe requests are excluded, so this only counts requests sent by the test.
 						if _, err := prom.QuerySum(c, appQuery); err != nil {
 							util.PromDiff(t, prom, c, appQuery)
 							return err
@@ -146,23 +173,30 @@ func TestStatsFilter(t *testing.T, feature features.Feature) {
 				t.Fatalf("test failed: %v", err)
 			}
 
-			// In addition, verifies that mocked prometheus could call metrics endpoint with proxy provisioned certs
-			for _, prom := range mockProm {
-				st := server.GetOrFail(t, echo.InCluster(prom.Config().Cluster))
-				prom.CallOrFail(t, echo.CallOptions{
-					Address: st.WorkloadsOrFail(t)[0].Address(),
-					Scheme:  scheme.HTTPS,
-					Port:    &echo.Port{ServicePort: 15014},
-					HTTP: echo.HTTP{
-						Path: "/metrics",
-					},
-					TLS: echo.TLS{
-						CertFile:           "/etc/certs/custom/cert-chain.pem",
-						KeyFile:            "/etc/certs/custom/key.pem",
-						CaCertFile:         "/etc/certs/custom/root-cert.pem",
-						InsecureSkipVerify: true,
-					},
-				})
+			// In addition, verify that Prometheus scraped the metrics endpoint of each server over mTLS, with the
+			// certificates provisioned by its sidecar. Since mTLS is STRICT, plaintext scraping would fail.
+			for _, srv := range server {
+				c := srv.Config().Cluster
+				for _, w := range srv.WorkloadsOrFail(t) {
+					query := prometheus.Query{
+						Metric: "up",
+						Labels: map[string]string{
+							"job":       prometheus.SecureScrapeJob,
+							"namespace": appNsInst.Name(),
+							"pod":       w.PodName(),
+						},
+					}
+					retry.UntilSuccessOrFail(t, func() error {
+						up, err := promInst.QuerySum(c, query)
+						if err != nil {
+							return err
+						}
+						if up != 1 {
+							return fmt.Errorf("expected %s to be scraped over mTLS, got up=%v", w.PodName(), up)
+						}
+						return nil
+					}, retry.Delay(framework.TelemetryRetryDelay), retry.Timeout(framework.TelemetryRetryTimeout))
+				}
 			}
 		})
 }
@@ -191,6 +225,13 @@ func TestStatsTCPFilter(t *testing.T, feature features.Feature) {
 							util.PromDiff(t, promInst, c, destinationQuery)
 							return err
 						}
+						// The echo client closes each connection after its exchange, so closed connections
+						// must be reported as well.
+						closedQuery := buildTCPClosedQuery(sourceCluster)
+						if _, err := GetPromInstance().Query(c, closedQuery); err != nil {
+							util.PromDiff(t, promInst, c, closedQuery)
+							return err
+						}
 
 						return nil
 					}, retry.Delay(framework.TelemetryRetryDelay), retry.Timeout(framework.TelemetryRetryTimeout))
@@ -216,10 +257,6 @@ func TestSetup(ctx resource.Context) (err error) {
 		return
 	}
 
-	outputCertAnnot := `
-proxyMetadata:
-  OUTPUT_CERTS: /etc/certs/custom`
-
 	echos, err := echoboot.NewBuilder(ctx).
 		WithClusters(ctx.Clusters()...).
 		With(nil, echo.Config{
@@ -231,12 +268,22 @@ proxyMetadata:
 		With(nil, echo.Config{
 			Service:   "server",
 			Namespace: appNsInst,
-			Subsets:   []echo.SubsetConfig{{}},
+			Subsets: []echo.SubsetConfig{
+				{
+					// Have Prometheus scrape the metrics endpoint of the echo app over mTLS as well.
+					Annotations: echo.NewAnnotations().Set(echo.Annotation{
+						Name: prometheus.SecurePortAnnotation,
+						Type: echo.WorkloadAnnotation,
+					}, strconv.Itoa(echoMetricsPort)).
+						// Allow tests to add this dimension to the metrics through the Telemetry API.
+						Set(echo.SidecarExtraStatTags, CustomDimension),
+				},
+			},
 			Ports: []echo.Port{
 				{
 					Name:         "http",
 					Protocol:     protocol.HTTP,
-					InstancePort: 8090,
+					InstancePort: serverHTTPPort,
 				},
 				{
 					Name:     "tcp",
@@ -245,6 +292,24 @@ proxyMetadata:
 					InstancePort: 9000,
 					ServicePort:  9000,
 				},
+				{
+					Name:         "grpc",
+					Protocol:     protocol.GRPC,
+					InstancePort: 7070,
+				},
+				{
+					// These port names carry no protocol prefix and no appProtocol is set, so the Service does
+					// not declare a protocol and Istio has to sniff it. Protocol only selects the echo server.
+					Name:         "sniffed-http",
+					Protocol:     protocol.HTTP,
+					InstancePort: 8091,
+				},
+				{
+					Name:         "sniffed-tcp",
+					Protocol:     protocol.TCP,
+					InstancePort: 9001,
+					ServicePort:  9001,
+				},
 			},
 		}).
 		With(nil, echo.Config{
@@ -273,34 +338,6 @@ proxyMetadata:
 					ServicePort:  9000,
 				},
 			},
-		}).
-		With(nil, echo.Config{
-			// mock prom instance is used to mock a prometheus server, which will visit other echo instance /metrics
-			// endpoint with proxy provisioned certs.
-			Service:   "mock-prom",
-			Namespace: appNsInst,
-			Subsets: []echo.SubsetConfig{
-				{
-					Annotations: map[echo.Annotation]*echo.AnnotationValue{
-						echo.SidecarIncludeInboundPorts: {
-							Value: "",
-						},
-						echo.SidecarIncludeOutboundIPRanges: {
-							Value: "",
-						},
-						echo.SidecarProxyConfig: {
-							Value: outputCertAnnot,
-						},
-						echo.SidecarVolumeMount: {
-							Value: `[{"name": "custom-certs", "mountPath": "/etc/certs/custom"}]`,
-						},
-					},
-				},
-			},
-			TLSSettings: &common.TLSSettings{
-				ProxyProvision: true,
-			},
-			Ports: []echo.Port{},
 		}).Build()
 	if err != nil {
 		return err
@@ -311,8 +348,7 @@ proxyMetadata:
 	client = echos.Match(echo.Service("client"))
 	server = echos.Match(echo.Service("server"))
 	nonInjectedServer = echos.Match(echo.Service("server-no-sidecar"))
-	mockProm = echos.Match(echo.Service("mock-prom"))
-	promInst, err = prometheus.New(ctx, prometheus.Config{})
+	promInst, err = prometheus.New(ctx, prometheus.Config{InMesh: true})
 	if err != nil {
 		return
 	}
@@ -347,6 +383,116 @@ func SendTraffic(cltInstance echo.Instance) error {
 	return nil
 }
 
+// CompareRuntimeMetrics sends the same traffic from cltInstance before and after switchRuntime is invoked,
+// for example to move the stats filter from the nullvm to the wasm runtime, and fails the test if the
+// increase of the destination-side istio_requests_total metric differs between the two runs by more
+// than tolerance, as a fraction of the first run.
+func CompareRuntimeMetrics(t framework.TestContext, cltInstance echo.Instance, switchRuntime func(t framework.TestContext),
+	tolerance float64) {
+	before := measureRequestIncrease(t, cltInstance)
+	if before == 0 {
+		t.Fatalf("no requests were recorded before switching runtimes")
+	}
+	switchRuntime(t)
+	after := measureRequestIncrease(t, cltInstance)
+	if diff := math.Abs(after - before); diff > tolerance*before {
+		t.Fatalf("metric increase differs between runtimes: %v before, %v after (tolerance %v)", before, after, tolerance)
+	}
+	t.Logf("metric increase matches between runtimes: %v before, %v after", before, after)
+}
+
+// measureRequestIncrease sends traffic from cltInstance and returns how much the destination-side
+// istio_requests_total metric grew. The metric is read once, after every server pod in the cluster has been
+// scraped twice since the traffic was sent, so that the result reflects what the runtime actually recorded
+// rather than the amount of traffic the test waited for.
+func measureRequestIncrease(t framework.TestContext, cltInstance echo.Instance) float64 {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	_, destinationQuery, _ := buildQuery(sourceCluster)
+	// The metric may not exist yet if no traffic has been recorded.
+	start, _ := promInst.QuerySum(c, destinationQuery)
+	if err := SendTraffic(cltInstance); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitForScrape(c, server.Match(echo.InCluster(c)), 2); err != nil {
+		t.Fatal(err)
+	}
+	current, err := promInst.QuerySum(c, destinationQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return current - start
+}
+
+// waitForScrape waits until each workload of the given instances has been scraped n times since it was
+// called, so that the metrics they reported for the traffic sent before can be queried.
+func waitForScrape(c cluster.Cluster, instances echo.Instances, n int) error {
+	var g errgroup.Group
+	for _, inst := range instances {
+		workloads, err := inst.Workloads()
+		if err != nil {
+			return err
+		}
+		for _, w := range workloads {
+			podName := w.PodName()
+			g.Go(func() error {
+				return promInst.WaitForScrapeCount(c, podName, n)
+			})
+		}
+	}
+	return g.Wait()
+}
+
+// waitForIncrease waits until the sum of the query has grown by at least expected since start, and returns
+// the observed increase.
+func waitForIncrease(t framework.TestContext, c cluster.Cluster, query prometheus.Query, start, expected float64) float64 {
+	var increase float64
+	retry.UntilSuccessOrFail(t, func() error {
+		current, err := promInst.QuerySum(c, query)
+		if err != nil {
+			return err
+		}
+		increase = current - start
+		if increase < expected {
+			return fmt.Errorf("expected an increase of at least %v for %v, got %v", expected, query, increase)
+		}
+		return nil
+	}, retry.Delay(framework.TelemetryRetryDelay), retry.Timeout(framework.TelemetryRetryTimeout))
+	return increase
+}
+
+// ValidateRequestCount sends HTTP traffic from cltInstance, and verifies that the destination-side
+// istio_requests_total metric grew by exactly the number of requests that were sent.
+func ValidateRequestCount(t framework.TestContext, cltInstance echo.Instance) {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	_, destinationQuery, _ := buildQuery(sourceCluster)
+	// The metric may not exist yet if no traffic has been recorded.
+	start, _ := promInst.QuerySum(c, destinationQuery)
+	count := util.RequestCountMultipler * len(server)
+	_, err := cltInstance.Call(echo.CallOptions{
+		Target:   server[0],
+		PortName: "http",
+		Count:    count,
+		Check:    check.And(check.OK(), check.ResponseCount(count)),
+		Retry: echo.Retry{
+			NoRetry: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if increase := waitForIncrease(t, c, destinationQuery, start, float64(count)); increase != float64(count) {
+		t.Fatalf("expected %v to increase by %d, got %v", destinationQuery, count, increase)
+	}
+}
+
 // SendTCPTraffic makes a client call to the "server" service on the tcp port.
 func SendTCPTraffic(cltInstance echo.Instance) error {
 	_, err := cltInstance.Call(echo.CallOptions{
@@ -363,6 +509,143 @@ func SendTCPTraffic(cltInstance echo.Instance) error {
 	return nil
 }
 
+// SendTCPTrafficWithPayload makes client calls to the "server" service on the tcp port, each sending a
+// payload of the given size.
+func SendTCPTrafficWithPayload(cltInstance echo.Instance, size int) error {
+	_, err := cltInstance.Call(echo.CallOptions{
+		Target:   server[0],
+		PortName: "tcp",
+		Count:    util.RequestCountMultipler * len(server),
+		Message:  strings.Repeat("a", size),
+		Retry: echo.Retry{
+			NoRetry: true,
+		},
+	})
+	return err
+}
+
+// ValidateTCPBytes sends TCP traffic with a payload of the given size from cltInstance, and verifies that the
+// destination-side istio_tcp_received_bytes_total and istio_tcp_sent_bytes_total metrics grew by at least the
+// bytes sent by the client and echoed back by the server. The server also sends its own response fields, so
+// the sent bytes are only checked as a lower bound.
+func ValidateTCPBytes(t framework.TestContext, cltInstance echo.Instance, size int) {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	received := buildTCPQuery(sourceCluster)
+	received.Metric = "istio_tcp_received_bytes_total"
+	sent := buildTCPQuery(sourceCluster)
+	sent.Metric = "istio_tcp_sent_bytes_total"
+	// The metrics may not exist yet if no traffic has been recorded.
+	receivedStart, _ := promInst.QuerySum(c, received)
+	sentStart, _ := promInst.QuerySum(c, sent)
+
+	if err := SendTCPTrafficWithPayload(cltInstance, size); err != nil {
+		t.Fatal(err)
+	}
+	// Each connection carries the payload followed by a newline.
+	expected := float64(util.RequestCountMultipler * len(server) * (size + 1))
+	waitForIncrease(t, c, received, receivedStart, expected)
+	waitForIncrease(t, c, sent, sentStart, expected)
+}
+
+// ValidateTCPConnectionsClosed sends TCP traffic from cltInstance, and verifies that the destination-side
+// istio_tcp_connections_closed_total metric grew by the number of connections, which the echo client closes
+// after each exchange.
+func ValidateTCPConnectionsClosed(t framework.TestContext, cltInstance echo.Instance) {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	closed := buildTCPClosedQuery(sourceCluster)
+	// The metric may not exist yet if no connection has been closed.
+	start, _ := promInst.QuerySum(c, closed)
+	if err := SendTCPTraffic(cltInstance); err != nil {
+		t.Fatal(err)
+	}
+	waitForIncrease(t, c, closed, start, float64(util.RequestCountMultipler*len(server)))
+}
+
+// ValidateSniffedProtocol sends traffic from cltInstance to the given port of the "server" service, on which
+// the protocol is sniffed, and verifies that the destination-side metrics report the given request_protocol.
+func ValidateSniffedProtocol(t framework.TestContext, cltInstance echo.Instance, portName, expectedProtocol string) {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	var query prometheus.Query
+	if expectedProtocol == "tcp" {
+		query = buildTCPQuery(sourceCluster)
+	} else {
+		_, query, _ = buildQuery(sourceCluster)
+		query.Labels["request_protocol"] = expectedProtocol
+	}
+	// The metric may not exist yet if no traffic has been recorded.
+	start, _ := promInst.QuerySum(c, query)
+	count := util.RequestCountMultipler * len(server)
+	_, err := cltInstance.Call(echo.CallOptions{
+		Target:   server[0],
+		PortName: portName,
+		Count:    count,
+		Retry: echo.Retry{
+			NoRetry: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForIncrease(t, c, query, start, float64(count))
+}
+
+// SendGRPCTraffic makes a client call to the "server" service on the grpc port.
+func SendGRPCTraffic(cltInstance echo.Instance) error {
+	_, err := cltInstance.Call(echo.CallOptions{
+		Target:   server[0],
+		PortName: "grpc",
+		Count:    util.RequestCountMultipler * len(server),
+		Check:    check.OK(),
+		Retry: echo.Retry{
+			NoRetry: true,
+		},
+	})
+	return err
+}
+
+// ValidateGRPCMetrics sends gRPC traffic from cltInstance, and verifies that the destination-side
+// istio_requests_total metric reports the requests with a grpc_response_status of OK, and that
+// istio_request_messages_total reports the streamed messages.
+func ValidateGRPCMetrics(t framework.TestContext, cltInstance echo.Instance) {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	requests, messages := buildGRPCQuery(sourceCluster)
+	// The metrics may not exist yet if no traffic has been recorded.
+	requestsStart, _ := promInst.QuerySum(c, requests)
+	messagesStart, _ := promInst.QuerySum(c, messages)
+	if err := SendGRPCTraffic(cltInstance); err != nil {
+		t.Fatal(err)
+	}
+	expected := float64(util.RequestCountMultipler * len(server))
+	waitForIncrease(t, c, requests, requestsStart, expected)
+	// Each unary call carries one request message.
+	waitForIncrease(t, c, messages, messagesStart, expected)
+}
+
+// ExcludeProbeTraffic returns a copy of an echo app metric query, such as istio_echo_http_requests_total,
+// restricted to the given application port. The echo metrics are only labeled by port, and the readiness
+// probe is served on its own port, so this excludes the ever-increasing probe requests.
+func ExcludeProbeTraffic(query prometheus.Query, port int) prometheus.Query {
+	query.Labels = clone(query.Labels)
+	query.Labels["port"] = strconv.Itoa(port)
+	return query
+}
+
 // BuildQueryCommon is the shared function to construct prom query for istio_request_total metric.
 func BuildQueryCommon(labels map[string]string, ns string) (sourceQuery, destinationQuery, appQuery prometheus.Query) {
 	sourceQuery.Metric = "istio_requests_total"
@@ -379,6 +662,147 @@ func BuildQueryCommon(labels map[string]string, ns string) (sourceQuery, destina
 	return
 }
 
+// ValidateLabelAbsent runs the query against the given cluster, and returns an error if it matches no series
+// or if any of the matched series still has the given label, e.g. after removing it with
+// ConfigIstio().RemoveMetricTags(). The query should only match series produced after the removal.
+func ValidateLabelAbsent(c cluster.Cluster, query prometheus.Query, label string) error {
+	val, err := promInst.Query(c, query)
+	if err != nil {
+		return err
+	}
+	vec, ok := val.(model.Vector)
+	if !ok {
+		return fmt.Errorf("value not a model.Vector; was %s", val.Type().String())
+	}
+	if len(vec) == 0 {
+		return fmt.Errorf("no series found (query: %v)", query)
+	}
+	for _, sample := range vec {
+		if v, f := sample.Metric[model.LabelName(label)]; f {
+			return fmt.Errorf("expected label %s to be absent, got %s=%q on %v", label, label, v, sample.Metric)
+		}
+	}
+	return nil
+}
+
+// ValidateCustomDimension sends HTTP traffic from cltInstance with the given header until the destination-side
+// istio_requests_total metric reports its value in CustomDimension, e.g. after setting the dimension from the
+// header with ConfigIstio().Telemetry().
+func ValidateCustomDimension(t framework.TestContext, cltInstance echo.Instance, header, value string) {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	_, destinationQuery, _ := buildQuery(sourceCluster)
+	query := WithDimensions(destinationQuery, map[string]string{CustomDimension: value})
+	retry.UntilSuccessOrFail(t, func() error {
+		_, err := cltInstance.Call(echo.CallOptions{
+			Target:   server[0],
+			PortName: "http",
+			Count:    util.RequestCountMultipler * len(server),
+			HTTP: echo.HTTP{
+				Headers: headers.New().With(header, value).Build(),
+			},
+			Retry: echo.Retry{
+				NoRetry: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = promInst.QuerySum(c, query)
+		return err
+	}, retry.Delay(framework.TelemetryRetryDelay), retry.Timeout(framework.TelemetryRetryTimeout))
+}
+
+// ValidateTagRemoved sends HTTP traffic from cltInstance until the destination-side istio_requests_total series
+// it produces no longer have the given tag, e.g. after removing it with ConfigIstio().RemoveMetricTags().
+// Every attempt asks the server for a different response code, so that series recorded before the removal
+// reached the proxies are not matched by later attempts.
+func ValidateTagRemoved(t framework.TestContext, cltInstance echo.Instance, tag string) {
+	c := cltInstance.Config().Cluster
+	sourceCluster := "Kubernetes"
+	if len(t.AllClusters()) > 1 {
+		sourceCluster = c.Name()
+	}
+	_, destinationQuery, _ := buildQuery(sourceCluster)
+	code := 450
+	retry.UntilSuccessOrFail(t, func() error {
+		code++
+		if code > 499 {
+			return fmt.Errorf("%s is still reported on %v", tag, destinationQuery.Metric)
+		}
+		query := WithDimensions(destinationQuery, map[string]string{"response_code": strconv.Itoa(code)})
+		delete(query.Labels, tag)
+		_, err := cltInstance.Call(echo.CallOptions{
+			Target:   server[0],
+			PortName: "http",
+			Count:    util.RequestCountMultipler * len(server),
+			HTTP: echo.HTTP{
+				Path: fmt.Sprintf("/?codes=%d:1", code),
+			},
+			Retry: echo.Retry{
+				NoRetry: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := retry.UntilSuccess(func() error {
+			_, err := promInst.QuerySum(c, query)
+			return err
+		}, retry.Delay(framework.TelemetryRetryDelay), retry.Timeout(framework.TelemetryRetryTimeout)); err != nil {
+			return err
+		}
+		return ValidateLabelAbsent(c, query, tag)
+	}, retry.Delay(framework.TelemetryRetryDelay), retry.Timeout(framework.TelemetryRetryTimeout))
+}
+
+// ValidateClusterLabels runs the query against the given cluster, and returns an error if it matches no series
+// or if any of the matched series was not reported with the given source_cluster and one of the given
+// destination_cluster values.
+func ValidateClusterLabels(c cluster.Cluster, query prometheus.Query, sourceCluster string, destinationClusters []string) error {
+	val, err := promInst.Query(c, query)
+	if err != nil {
+		return err
+	}
+	vec, ok := val.(model.Vector)
+	if !ok {
+		return fmt.Errorf("value not a model.Vector; was %s", val.Type().String())
+	}
+	if len(vec) == 0 {
+		return fmt.Errorf("no series found (query: %v)", query)
+	}
+	for _, sample := range vec {
+		if got := string(sample.Metric["source_cluster"]); got != sourceCluster {
+			return fmt.Errorf("expected source_cluster %q, got %q on %v", sourceCluster, got, sample.Metric)
+		}
+		got := string(sample.Metric["destination_cluster"])
+		found := false
+		for _, want := range destinationClusters {
+			if got == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("expected destination_cluster to be one of %v, got %q on %v", destinationClusters, got, sample.Metric)
+		}
+	}
+	return nil
+}
+
+// WithDimensions returns a copy of the query that additionally matches the given custom dimensions, such
+// as those added through ConfigIstio().Telemetry().
+func WithDimensions(query prometheus.Query, dimensions map[string]string) prometheus.Query {
+	query.Labels = clone(query.Labels)
+	for k, v := range dimensions {
+		query.Labels[k] = v
+	}
+	return query
+}
+
 func clone(labels map[string]string) map[string]string {
 	ret := map[string]string{}
 	for k, v := range labels {
@@ -405,7 +829,9 @@ func buildQuery(sourceCluster string) (sourceQuery, destinationQuery, appQuery p
 		"source_cluster":                 sourceCluster,
 	}
 
-	return BuildQueryCommon(labels, ns.Name())
+	sourceQuery, destinationQuery, appQuery = BuildQueryCommon(labels, ns.Name())
+	appQuery = ExcludeProbeTraffic(appQuery, serverHTTPPort)
+	return
 }
 
 func buildOutOfMeshServerQuery(sourceCluster string) prometheus.Query {
@@ -434,6 +860,21 @@ func buildOutOfMeshServerQuery(sourceCluster string) prometheus.Query {
 	return source
 }
 
+// buildGRPCQuery returns the destination-side queries for successful gRPC requests and for their request
+// messages.
+func buildGRPCQuery(sourceCluster string) (requestsQuery, messagesQuery prometheus.Query) {
+	_, requestsQuery, _ = buildQuery(sourceCluster)
+	requestsQuery.Labels["request_protocol"] = "grpc"
+	requestsQuery.Labels["grpc_response_status"] = "0"
+
+	messagesQuery.Metric = "istio_request_messages_total"
+	messagesQuery.Labels = clone(requestsQuery.Labels)
+	// Message counters do not carry the response labels.
+	delete(messagesQuery.Labels, "response_code")
+	delete(messagesQuery.Labels, "grpc_response_status")
+	return
+}
+
 func buildTCPQuery(sourceCluster string) (destinationQuery prometheus.Query) {
 	ns := GetAppNamespace()
 	labels := map[string]string{
@@ -457,3 +898,10 @@ func buildTCPQuery(sourceCluster string) (destinationQuery prometheus.Query) {
 		Labels: labels,
 	}
 }
+
+// buildTCPClosedQuery returns the same query as buildTCPQuery, for closed rather than opened connections.
+func buildTCPClosedQuery(sourceCluster string) prometheus.Query {
+	q := buildTCPQuery(sourceCluster)
+	q.Metric = "istio_tcp_connections_closed_total"
+	return q
+}